@@ -0,0 +1,128 @@
+// Copyright 2019 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// gopherwalk-solve searches for the solutions with the fewest taps of fields. gopherwalk-solve exits with 1 if any
+// field has no solution or has a par different from the fewest taps.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hajimehoshi/gopherwalk/internal/level"
+	"github.com/hajimehoshi/gopherwalk/internal/levelpack"
+	"github.com/hajimehoshi/gopherwalk/internal/sim"
+)
+
+var (
+	fieldsDir = flag.String("fields", "", "directory of field files to solve instead of the embedded level pack")
+	fieldID   = flag.Int("field", 0, "ID of the field to solve (0 for all the fields)")
+	maxTicks  = flag.Int("ticks", 20000, "maximum number of ticks to reach the goal")
+	maxStates = flag.Int("states", 1000000, "maximum number of states to search per field")
+	outDir    = flag.String("out", "", "directory to save replays of the solutions")
+)
+
+func loadPack() (*level.Pack, error) {
+	if *fieldsDir != "" {
+		return level.LoadPack(*fieldsDir)
+	}
+	return levelpack.Load()
+}
+
+func saveReplay(lv *level.Level, s *sim.Solution) error {
+	r := &sim.Replay{
+		FileName: lv.FileName,
+		Taps:     s.Taps,
+	}
+	data, err := r.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(*outDir, strings.TrimSuffix(lv.FileName, level.Ext)+sim.ReplayExt), data, 0644)
+}
+
+func solve(id int, lv *level.Level) (bool, error) {
+	s, err := sim.Solve(lv, *maxTicks, *maxStates)
+	switch err {
+	case nil:
+	case sim.ErrNoSolution:
+		fmt.Printf("%d %s: no solution within %d ticks\n", id, lv.Name, *maxTicks)
+		return false, nil
+	case sim.ErrTooManyStates:
+		fmt.Printf("%d %s: more than %d states to search, the limit of -states\n", id, lv.Name, *maxStates)
+		return false, nil
+	default:
+		return false, err
+	}
+
+	fmt.Printf("%d %s: %d taps, goal at tick %d\n", id, lv.Name, len(s.Taps), s.Ticks)
+	for _, t := range s.Taps {
+		fmt.Printf("\ttick %d: tap (%d, %d)\n", t.Tick, t.X, t.Y)
+	}
+	if *outDir != "" {
+		if err := saveReplay(lv, s); err != nil {
+			return false, err
+		}
+	}
+	if lv.Par > 0 && lv.Par != len(s.Taps) {
+		fmt.Printf("%d %s: par %d differs from the fewest taps %d\n", id, lv.Name, lv.Par, len(s.Taps))
+		return false, nil
+	}
+	return true, nil
+}
+
+func run() (bool, error) {
+	pack, err := loadPack()
+	if err != nil {
+		return false, err
+	}
+
+	if *fieldID != 0 {
+		lv, ok := pack.Level(*fieldID)
+		if !ok {
+			return false, fmt.Errorf("field %d not found", *fieldID)
+		}
+		return solve(*fieldID, lv)
+	}
+
+	ok := true
+	for id := 1; id <= pack.Len(); id++ {
+		lv, _ := pack.Level(id)
+		solved, err := solve(id, lv)
+		if err != nil {
+			return false, err
+		}
+		if !solved {
+			ok = false
+		}
+	}
+	return ok, nil
+}
+
+func main() {
+	flag.Parse()
+
+	ok, err := run()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if !ok {
+		os.Exit(1)
+	}
+}
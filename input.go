@@ -0,0 +1,123 @@
+// Copyright 2019 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/inpututil"
+
+	"github.com/hajimehoshi/gopherwalk/internal/binding"
+	"github.com/hajimehoshi/gopherwalk/internal/scene"
+)
+
+// rawInput is the input of the current frame regardless of scene updates.
+type rawInput struct {
+	bindings binding.Table
+}
+
+// CursorPosition returns the position of the first touch if any, or the mouse cursor.
+func (r rawInput) CursorPosition() (x, y int) {
+	if ts := r.JustStartedTouches(); len(ts) > 0 {
+		return ts[0].X, ts[0].Y
+	}
+	if ids := ebiten.TouchIDs(); len(ids) > 0 {
+		return ebiten.TouchPosition(ids[0])
+	}
+	return ebiten.CursorPosition()
+}
+
+func (r rawInput) IsJustTapped() bool {
+	return len(r.JustStartedTouches()) > 0
+}
+
+func (rawInput) JustStartedTouches() []scene.Touch {
+	var ts []scene.Touch
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		x, y := ebiten.CursorPosition()
+		ts = append(ts, scene.Touch{
+			ID: scene.MouseTouchID,
+			X:  x,
+			Y:  y,
+		})
+	}
+	for _, id := range inpututil.JustPressedTouchIDs() {
+		x, y := ebiten.TouchPosition(id)
+		ts = append(ts, scene.Touch{
+			ID: id,
+			X:  x,
+			Y:  y,
+		})
+	}
+	return ts
+}
+
+func (rawInput) IsPressed() bool {
+	return ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) || len(ebiten.TouchIDs()) > 0
+}
+
+func (rawInput) IsKeyJustPressed(key ebiten.Key) bool {
+	return inpututil.IsKeyJustPressed(key)
+}
+
+func (rawInput) IsKeyPressed(key ebiten.Key) bool {
+	return ebiten.IsKeyPressed(key)
+}
+
+func (rawInput) IsGamepadButtonJustPressed(button ebiten.GamepadButton) bool {
+	for _, id := range ebiten.GamepadIDs() {
+		if inpututil.IsGamepadButtonJustPressed(id, button) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r rawInput) IsActionJustPressed(action scene.Action) bool {
+	b, ok := r.bindings[action]
+	if !ok {
+		return false
+	}
+	for _, k := range b.Keys {
+		if inpututil.IsKeyJustPressed(k) {
+			return true
+		}
+	}
+	for _, gb := range b.GamepadButtons {
+		if r.IsGamepadButtonJustPressed(gb) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r rawInput) IsActionPressed(action scene.Action) bool {
+	b, ok := r.bindings[action]
+	if !ok {
+		return false
+	}
+	for _, k := range b.Keys {
+		if ebiten.IsKeyPressed(k) {
+			return true
+		}
+	}
+	for _, id := range ebiten.GamepadIDs() {
+		for _, gb := range b.GamepadButtons {
+			if ebiten.IsGamepadButtonPressed(id, gb) {
+				return true
+			}
+		}
+	}
+	return false
+}
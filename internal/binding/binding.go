@@ -0,0 +1,202 @@
+// Copyright 2019 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package binding
+
+import (
+	"github.com/hajimehoshi/ebiten"
+
+	"github.com/hajimehoshi/gopherwalk/internal/save"
+	"github.com/hajimehoshi/gopherwalk/internal/scene"
+)
+
+// Actions is the rebindable actions in the order shown to the user.
+var Actions = []scene.Action{
+	scene.ActionUp,
+	scene.ActionDown,
+	scene.ActionLeft,
+	scene.ActionRight,
+	scene.ActionConfirm,
+	scene.ActionCancel,
+	scene.ActionPause,
+	scene.ActionStep,
+	scene.ActionSlower,
+	scene.ActionFaster,
+	scene.ActionTurbo,
+	scene.ActionRestart,
+	scene.ActionRewind,
+	scene.ActionDebug,
+}
+
+// Binding is the keys and gamepad buttons bound to an action.
+type Binding struct {
+	Keys           []ebiten.Key
+	GamepadButtons []ebiten.GamepadButton
+}
+
+// Table is the bindings of all the actions.
+type Table map[scene.Action]*Binding
+
+// Default returns the default bindings.
+//
+// The gamepad buttons are for XInput gamepads like Xbox controllers.
+// Ebiten doesn't provide the standard layout of gamepads yet.
+func Default() Table {
+	return Table{
+		scene.ActionUp: {
+			Keys:           []ebiten.Key{ebiten.KeyUp},
+			GamepadButtons: []ebiten.GamepadButton{ebiten.GamepadButton10},
+		},
+		scene.ActionDown: {
+			Keys:           []ebiten.Key{ebiten.KeyDown},
+			GamepadButtons: []ebiten.GamepadButton{ebiten.GamepadButton12},
+		},
+		scene.ActionLeft: {
+			Keys:           []ebiten.Key{ebiten.KeyLeft},
+			GamepadButtons: []ebiten.GamepadButton{ebiten.GamepadButton13},
+		},
+		scene.ActionRight: {
+			Keys:           []ebiten.Key{ebiten.KeyRight},
+			GamepadButtons: []ebiten.GamepadButton{ebiten.GamepadButton11},
+		},
+		scene.ActionConfirm: {
+			Keys:           []ebiten.Key{ebiten.KeyEnter, ebiten.KeySpace},
+			GamepadButtons: []ebiten.GamepadButton{ebiten.GamepadButton0},
+		},
+		scene.ActionCancel: {
+			Keys:           []ebiten.Key{ebiten.KeyEscape},
+			GamepadButtons: []ebiten.GamepadButton{ebiten.GamepadButton1, ebiten.GamepadButton7},
+		},
+		scene.ActionPause: {
+			Keys: []ebiten.Key{ebiten.KeyP},
+		},
+		scene.ActionStep: {
+			Keys: []ebiten.Key{ebiten.KeyPeriod},
+		},
+		scene.ActionSlower: {
+			Keys: []ebiten.Key{ebiten.KeyMinus},
+		},
+		scene.ActionFaster: {
+			Keys: []ebiten.Key{ebiten.KeyEqual},
+		},
+		scene.ActionTurbo: {
+			Keys:           []ebiten.Key{ebiten.KeyT},
+			GamepadButtons: []ebiten.GamepadButton{ebiten.GamepadButton5},
+		},
+		scene.ActionRestart: {
+			Keys:           []ebiten.Key{ebiten.KeyR},
+			GamepadButtons: []ebiten.GamepadButton{ebiten.GamepadButton6},
+		},
+		scene.ActionRewind: {
+			Keys:           []ebiten.Key{ebiten.KeyBackspace},
+			GamepadButtons: []ebiten.GamepadButton{ebiten.GamepadButton4},
+		},
+		scene.ActionDebug: {
+			Keys: []ebiten.Key{ebiten.KeyF3},
+		},
+	}
+}
+
+// keyByName returns the key of the name returned by ebiten.Key's String.
+func keyByName(name string) (ebiten.Key, bool) {
+	for k := ebiten.Key(0); k <= ebiten.KeyMax; k++ {
+		if k.String() == name {
+			return k, true
+		}
+	}
+	return 0, false
+}
+
+// FromSettings returns the bindings in the settings. Actions not in the settings have the default bindings.
+// Unknown actions, keys and buttons in the settings are ignored.
+func FromSettings(settings *save.Settings) Table {
+	t := Default()
+	for _, a := range Actions {
+		sb, ok := settings.Bindings[a.String()]
+		if !ok || sb == nil {
+			continue
+		}
+		b := &Binding{}
+		for _, name := range sb.Keys {
+			if k, ok := keyByName(name); ok {
+				b.Keys = append(b.Keys, k)
+			}
+		}
+		for _, n := range sb.GamepadButtons {
+			if n >= 0 && ebiten.GamepadButton(n) <= ebiten.GamepadButtonMax {
+				b.GamepadButtons = append(b.GamepadButtons, ebiten.GamepadButton(n))
+			}
+		}
+		t[a] = b
+	}
+	return t
+}
+
+// Save stores the bindings in the settings.
+func (t Table) Save(settings *save.Settings) {
+	settings.Bindings = map[string]*save.Binding{}
+	for _, a := range Actions {
+		b, ok := t[a]
+		if !ok {
+			continue
+		}
+		sb := &save.Binding{
+			Keys:           []string{},
+			GamepadButtons: []int{},
+		}
+		for _, k := range b.Keys {
+			sb.Keys = append(sb.Keys, k.String())
+		}
+		for _, gb := range b.GamepadButtons {
+			sb.GamepadButtons = append(sb.GamepadButtons, int(gb))
+		}
+		settings.Bindings[a.String()] = sb
+	}
+}
+
+// Conflicts returns the actions that share a key or a gamepad button with another action.
+// Such actions would be triggered at the same time.
+func (t Table) Conflicts() map[scene.Action]bool {
+	keys := map[ebiten.Key][]scene.Action{}
+	buttons := map[ebiten.GamepadButton][]scene.Action{}
+	for _, a := range Actions {
+		b, ok := t[a]
+		if !ok {
+			continue
+		}
+		for _, k := range b.Keys {
+			keys[k] = append(keys[k], a)
+		}
+		for _, gb := range b.GamepadButtons {
+			buttons[gb] = append(buttons[gb], a)
+		}
+	}
+
+	c := map[scene.Action]bool{}
+	for _, as := range keys {
+		if len(as) > 1 {
+			for _, a := range as {
+				c[a] = true
+			}
+		}
+	}
+	for _, as := range buttons {
+		if len(as) > 1 {
+			for _, a := range as {
+				c[a] = true
+			}
+		}
+	}
+	return c
+}
@@ -0,0 +1,325 @@
+// Copyright 2019 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package editorscene
+
+import (
+	"fmt"
+	"image/color"
+	"io/ioutil"
+	"strings"
+	"unicode"
+
+	"github.com/hajimehoshi/bitmapfont"
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/ebitenutil"
+	"github.com/hajimehoshi/ebiten/text"
+
+	"github.com/hajimehoshi/gopherwalk/internal/gamescene"
+	"github.com/hajimehoshi/gopherwalk/internal/level"
+	"github.com/hajimehoshi/gopherwalk/internal/scene"
+)
+
+const (
+	tileWidth  = 16
+	tileHeight = 16
+)
+
+type brush struct {
+	key   ebiten.Key
+	glyph rune
+	name  string
+}
+
+var brushes = []brush{
+	{ebiten.Key1, level.GlyphWall, "Wall"},
+	{ebiten.Key2, level.GlyphBigWall, "Big wall"},
+	{ebiten.Key3, level.GlyphForceField, "Force field"},
+	{ebiten.Key4, level.GlyphBigForceField, "Big force field"},
+	{ebiten.Key5, level.GlyphElevator, "Elevator"},
+	{ebiten.Key6, level.GlyphStart, "Start"},
+	{ebiten.Key7, level.GlyphGoal, "Goal"},
+	{ebiten.Key8, level.GlyphDownElevator, "Down elevator"},
+	{ebiten.Key9, level.GlyphToggleElevator, "Toggle elevator"},
+	{ebiten.KeyQ, level.GlyphRedKey, "Red key"},
+	{ebiten.KeyW, level.GlyphRedDoor, "Red door"},
+	{ebiten.KeyE, level.GlyphBlueKey, "Blue key"},
+	{ebiten.KeyR, level.GlyphBlueDoor, "Blue door"},
+	{ebiten.KeyT, level.GlyphYellowKey, "Yellow key"},
+	{ebiten.KeyY, level.GlyphYellowDoor, "Yellow door"},
+	{ebiten.KeyZ, level.GlyphSpikes, "Spikes"},
+	{ebiten.KeyX, level.GlyphLava, "Lava"},
+	{ebiten.Key0, level.GlyphEmpty, "Eraser"},
+}
+
+// channelKeys is the keys of the brushes of the switches and the linked force fields in the order of level.ChannelLetters.
+var channelKeys = []ebiten.Key{ebiten.KeyA, ebiten.KeyD, ebiten.KeyF, ebiten.KeyG, ebiten.KeyH, ebiten.KeyJ, ebiten.KeyK, ebiten.KeyL}
+
+// teleporterKeys is the keys of the brushes of the teleporters 1 to 5.
+var teleporterKeys = []ebiten.Key{ebiten.KeyC, ebiten.KeyV, ebiten.KeyB, ebiten.KeyN, ebiten.KeyM}
+
+func init() {
+	for i, k := range teleporterKeys {
+		c := rune(level.TeleporterDigits[i+1])
+		brushes = append(brushes, brush{k, c, fmt.Sprintf("Teleporter %c", c)})
+	}
+	for i, c := range level.ChannelLetters {
+		u := unicode.ToUpper(c)
+		brushes = append(brushes,
+			brush{channelKeys[2*i], c, fmt.Sprintf("Switch %c", c)},
+			brush{channelKeys[2*i+1], u, fmt.Sprintf("Force field %c", u)})
+	}
+}
+
+type EditorScene struct {
+	level *level.Level
+	grid  [level.ScreenHeight][level.ScreenWidth]rune
+	path  string
+
+	// tooLarge indicates whether the field is larger than a screen.
+	// The editor edits only one screen, so such a field is not saved not to lose the rest.
+	tooLarge bool
+
+	brush   int
+	message string
+
+	playing *gamescene.GameScene
+}
+
+// New creates an editor of the field. The field is saved to path.
+func New(lv *level.Level, path string) *EditorScene {
+	s := &EditorScene{
+		level: lv,
+		path:  path,
+	}
+	for j := range s.grid {
+		for i := range s.grid[j] {
+			s.grid[j][i] = level.GlyphEmpty
+		}
+	}
+	for j, line := range strings.Split(lv.Field, "\n") {
+		for i, c := range []rune(line) {
+			if j < level.ScreenHeight && i < level.ScreenWidth {
+				s.grid[j][i] = c
+				continue
+			}
+			s.tooLarge = true
+		}
+	}
+	s.message = "1-9, Q-Y, A-L, Z-M: Brush, 0: Eraser\nEnter: Play, S: Save"
+	if s.tooLarge {
+		s.message = "Only the first screen is editable. S is disabled."
+	}
+	return s
+}
+
+func (s *EditorScene) currentLevel() *level.Level {
+	var lines []string
+	for _, row := range s.grid {
+		lines = append(lines, string(row[:]))
+	}
+	lv := *s.level
+	lv.Field = strings.Join(lines, "\n")
+	return &lv
+}
+
+// validLevel returns the current level validated by the parser.
+func (s *EditorScene) validLevel() (*level.Level, error) {
+	return level.Parse(s.level.FileName, level.Format(s.currentLevel()))
+}
+
+func (s *EditorScene) at(x, y int) rune {
+	if x < 0 || level.ScreenWidth <= x || y < 0 || level.ScreenHeight <= y {
+		return 0
+	}
+	return s.grid[y][x]
+}
+
+// erase erases the tile at (x, y). If the tile is a part of a big tile, the whole big tile is erased.
+func (s *EditorScene) erase(x, y int) {
+	switch c := s.at(x, y); {
+	case c == 0:
+		return
+	case level.IsBig(c):
+		for j := y; j < y+2; j++ {
+			for i := x; i < x+2; i++ {
+				if s.at(i, j) != 0 {
+					s.grid[j][i] = level.GlyphEmpty
+				}
+			}
+		}
+	case c == level.GlyphFiller:
+		s.grid[y][x] = level.GlyphEmpty
+		for _, d := range [][2]int{{-1, 0}, {0, -1}, {-1, -1}} {
+			if level.IsBig(s.at(x+d[0], y+d[1])) {
+				s.erase(x+d[0], y+d[1])
+			}
+		}
+	default:
+		s.grid[y][x] = level.GlyphEmpty
+	}
+}
+
+func (s *EditorScene) paint(x, y int, glyph rune) {
+	if glyph == level.GlyphStart {
+		for j := range s.grid {
+			for i := range s.grid[j] {
+				if s.grid[j][i] == level.GlyphStart {
+					s.grid[j][i] = level.GlyphEmpty
+				}
+			}
+		}
+	}
+
+	if !level.IsBig(glyph) {
+		s.erase(x, y)
+		s.grid[y][x] = glyph
+		return
+	}
+
+	if s.at(x+1, y+1) == 0 {
+		return
+	}
+	for j := y; j < y+2; j++ {
+		for i := x; i < x+2; i++ {
+			s.erase(i, j)
+			s.grid[j][i] = level.GlyphFiller
+		}
+	}
+	s.grid[y][x] = glyph
+}
+
+func (s *EditorScene) save() error {
+	if s.tooLarge {
+		return fmt.Errorf("editorscene: %s is larger than a screen", s.level.FileName)
+	}
+	lv, err := s.validLevel()
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(s.path, level.Format(lv), 0644); err != nil {
+		return err
+	}
+	s.level = lv
+	return nil
+}
+
+func firstLine(err error) string {
+	return strings.SplitN(err.Error(), "\n", 2)[0]
+}
+
+// IsCapturing implements scene.Capturer.
+// The editor takes letter keys for the brushes, so the speed controls like the turbo on T are disabled while editing.
+func (s *EditorScene) IsCapturing() bool {
+	return s.playing == nil
+}
+
+func (s *EditorScene) Update(context scene.Context) error {
+	input := context.Input()
+
+	if s.playing != nil {
+		if input.IsKeyJustPressed(ebiten.KeyEscape) {
+			s.playing = nil
+			return nil
+		}
+		if s.playing.AtGoal() {
+			s.playing = nil
+			s.message = "Cleared!"
+			return nil
+		}
+		return s.playing.Update(context)
+	}
+
+	if input.IsKeyJustPressed(ebiten.KeyEscape) {
+		context.GoToFieldSelectorScene()
+		return nil
+	}
+
+	for i, b := range brushes {
+		if input.IsKeyJustPressed(b.key) {
+			s.brush = i
+			s.message = b.name
+		}
+	}
+
+	if input.IsKeyJustPressed(ebiten.KeyEnter) {
+		lv, err := s.validLevel()
+		if err != nil {
+			s.message = firstLine(err)
+			return nil
+		}
+		g, err := gamescene.New(0, lv)
+		if err != nil {
+			s.message = firstLine(err)
+			return nil
+		}
+		s.playing = g
+		s.message = "Esc: Back to the editor"
+		return nil
+	}
+
+	if input.IsKeyJustPressed(ebiten.KeyS) {
+		if err := s.save(); err != nil {
+			s.message = firstLine(err)
+			return nil
+		}
+		s.message = fmt.Sprintf("Saved to %s", s.path)
+		return nil
+	}
+
+	// Big tiles are painted only by taps, or dragging would paint overlapping tiles.
+	b := brushes[s.brush]
+	if (level.IsBig(b.glyph) && input.IsJustTapped()) || (!level.IsBig(b.glyph) && input.IsPressed()) {
+		x, y := input.CursorPosition()
+		x /= tileWidth
+		y /= tileHeight
+		if c := s.at(x, y); c != 0 && c != b.glyph {
+			s.paint(x, y, b.glyph)
+		}
+	}
+
+	return nil
+}
+
+func (s *EditorScene) Draw(screen *ebiten.Image) {
+	if s.playing != nil {
+		s.playing.Draw(screen)
+		return
+	}
+
+	gamescene.DrawLevel(screen, s.currentLevel())
+
+	for j, row := range s.grid {
+		for i, c := range row {
+			if c != level.GlyphStart {
+				continue
+			}
+			x := float64(i * tileWidth)
+			y := float64(j * tileHeight)
+			ebitenutil.DrawRect(screen, x, y, tileWidth, tileHeight, color.NRGBA{0x00, 0xff, 0x00, 0x80})
+		}
+	}
+
+	clr := color.NRGBA{0, 0, 0, 0x20}
+	for i := 1; i < level.ScreenWidth; i++ {
+		x := float64(i * tileWidth)
+		ebitenutil.DrawLine(screen, x, 0, x, level.ScreenHeight*tileHeight, clr)
+	}
+	for j := 1; j < level.ScreenHeight; j++ {
+		y := float64(j * tileHeight)
+		ebitenutil.DrawLine(screen, 0, y, level.ScreenWidth*tileWidth, y, clr)
+	}
+
+	text.Draw(screen, s.message, bitmapfont.Gothic12r, 4, 12, color.Black)
+}
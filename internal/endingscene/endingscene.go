@@ -0,0 +1,83 @@
+// Copyright 2019 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package endingscene
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/bitmapfont"
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/text"
+
+	"github.com/hajimehoshi/gopherwalk/internal/level"
+	"github.com/hajimehoshi/gopherwalk/internal/scene"
+)
+
+const (
+	lineHeight = 16
+)
+
+type EndingScene struct {
+	lines []string
+}
+
+func New(pack *level.Pack) *EndingScene {
+	name := pack.Name
+	if name == "" {
+		name = "the pack"
+	}
+	lines := []string{
+		"Congratulations!",
+		fmt.Sprintf("You cleared all the fields of %s.", name),
+		"",
+		"Fields by",
+	}
+
+	// List the authors in order of appearance.
+	authors := map[string]bool{}
+	for _, w := range pack.Worlds {
+		for _, l := range w.Levels {
+			if l.Author == "" || authors[l.Author] {
+				continue
+			}
+			authors[l.Author] = true
+			lines = append(lines, "  "+l.Author)
+		}
+	}
+	if len(authors) == 0 {
+		lines = lines[:len(lines)-2]
+	}
+
+	lines = append(lines, "", "Thank you for playing!")
+	return &EndingScene{
+		lines: lines,
+	}
+}
+
+func (s *EndingScene) Update(context scene.Context) error {
+	if context.Input().IsJustTapped() || context.Input().IsActionJustPressed(scene.ActionConfirm) {
+		context.GoToTitleScene()
+		return nil
+	}
+	return nil
+}
+
+func (s *EndingScene) Draw(screen *ebiten.Image) {
+	screen.Fill(color.White)
+	for i, l := range s.lines {
+		text.Draw(screen, l, bitmapfont.Gothic12r, 16, 24+i*lineHeight+12, color.Black)
+	}
+}
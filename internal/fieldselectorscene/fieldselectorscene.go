@@ -21,18 +21,30 @@ import (
 
 	"github.com/hajimehoshi/bitmapfont"
 	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/ebitenutil"
 	"github.com/hajimehoshi/ebiten/text"
-	"golang.org/x/image/font"
 
+	"github.com/hajimehoshi/gopherwalk/internal/level"
 	"github.com/hajimehoshi/gopherwalk/internal/scene"
+	"github.com/hajimehoshi/gopherwalk/internal/ui"
 )
 
 type FieldSelectorScene struct {
-	fieldButtons []*Button
+	fieldButtons []*ui.Button
+	cleared      []bool
+	focus        ui.Focus
+	labels       []*label
 	selected     int
 }
 
-func New() *FieldSelectorScene {
+type label struct {
+	text string
+	x    int
+	y    int
+}
+
+// New creates a field selector. cleared reports whether the field of the given ID is cleared.
+func New(pack *level.Pack, cleared func(id int) bool) *FieldSelectorScene {
 	const (
 		w = 24
 		h = 16
@@ -40,16 +52,31 @@ func New() *FieldSelectorScene {
 
 	s := &FieldSelectorScene{}
 
-	var bs []*Button
-	for i := 0; i < 30; i++ {
-		id := i + 1
-		x := (i%10)*w + 8
-		y := (i/10)*h + 8
-		b := NewButton(image.Rect(x, y, x+w, y+h), fmt.Sprintf("%d", id))
-		b.SetOnTap(func() {
-			s.selected = id
+	var bs []*ui.Button
+	id := 1
+	y := 8
+	for _, world := range pack.Worlds {
+		s.labels = append(s.labels, &label{
+			text: world.Name,
+			x:    8,
+			y:    y,
 		})
-		bs = append(bs, b)
+		y += lineHeight
+
+		for i := range world.Levels {
+			id := id + i
+			x := (i%10)*w + 8
+			y := y + (i/10)*h
+			b := ui.NewButton(image.Rect(x, y, x+w, y+h), fmt.Sprintf("%d", id))
+			b.SetOnTap(func() {
+				s.selected = id
+			})
+			b.SetDisabled(!pack.IsUnlocked(id, cleared))
+			bs = append(bs, b)
+			s.cleared = append(s.cleared, cleared(id))
+		}
+		id += len(world.Levels)
+		y += (len(world.Levels)+9)/10*h + 8
 	}
 	s.fieldButtons = bs
 
@@ -60,15 +87,33 @@ func (s *FieldSelectorScene) Update(context scene.Context) error {
 	for _, b := range s.fieldButtons {
 		b.Update(context.Input())
 	}
+	s.focus.Update(context.Input(), s.fieldButtons)
 	if s.selected != 0 {
 		context.GoToGameScene(s.selected)
+		return nil
+	}
+	if context.Input().IsKeyJustPressed(ebiten.KeyE) {
+		id := 0
+		for i, b := range s.fieldButtons {
+			if b.IsHovered() || b.IsFocused() {
+				id = i + 1
+			}
+		}
+		context.GoToEditorScene(id)
 	}
 	return nil
 }
 
 func (s *FieldSelectorScene) Draw(screen *ebiten.Image) {
 	screen.Fill(color.White)
-	for _, b := range s.fieldButtons {
+	for _, l := range s.labels {
+		text.Draw(screen, l.text, bitmapfont.Gothic12r, l.x, l.y+12, color.Black)
+	}
+	for i, b := range s.fieldButtons {
+		if s.cleared[i] {
+			r := b.Rect()
+			ebitenutil.DrawRect(screen, float64(r.Min.X+1), float64(r.Min.Y+1), float64(r.Dx()-2), float64(r.Dy()-2), color.NRGBA{0xcc, 0xff, 0xcc, 0xff})
+		}
 		b.Draw(screen)
 	}
 }
@@ -76,42 +121,3 @@ func (s *FieldSelectorScene) Draw(screen *ebiten.Image) {
 const (
 	lineHeight = 16
 )
-
-type Button struct {
-	rect  image.Rectangle
-	text  string
-	ontap func()
-	hover bool
-}
-
-func NewButton(rect image.Rectangle, text string) *Button {
-	return &Button{
-		rect: rect,
-		text: text,
-	}
-}
-
-func (b *Button) SetOnTap(f func()) {
-	b.ontap = f
-}
-
-func (b *Button) Update(input scene.Input) {
-	x, y := input.CursorPosition()
-	b.hover = image.Pt(x, y).In(b.rect)
-	if b.hover && input.IsJustTapped() && b.ontap != nil {
-		b.ontap()
-	}
-}
-
-func (b *Button) Draw(screen *ebiten.Image) {
-	bound, _ := font.BoundString(bitmapfont.Gothic12r, b.text)
-	bw := (bound.Max.X - bound.Min.X).Ceil()
-	bh := (bound.Max.Y - bound.Min.Y).Ceil()
-	x := b.rect.Min.X + (b.rect.Dx()-bw)/2 + 4
-	y := b.rect.Min.Y + (b.rect.Dy()-bh)/2 + 12
-	clr := color.NRGBA{0, 0, 0, 0xff}
-	if b.hover {
-		clr = color.NRGBA{0xff, 0, 0, 0xff}
-	}
-	text.Draw(screen, b.text, bitmapfont.Gothic12r, x, y, clr)
-}
@@ -0,0 +1,164 @@
+// Copyright 2019 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gamescene
+
+import (
+	"image"
+
+	"github.com/hajimehoshi/gopherwalk/internal/level"
+	"github.com/hajimehoshi/gopherwalk/internal/scene"
+	"github.com/hajimehoshi/gopherwalk/internal/sim"
+)
+
+const (
+	viewWidth  = level.ScreenWidth * tileWidth
+	viewHeight = level.ScreenHeight * tileHeight
+)
+
+const (
+	// dragThreshold is the distance in pixels a press has to move to start panning.
+	dragThreshold = 4
+
+	// panDuration is the number of ticks the camera stays after panning before following the gopher again.
+	panDuration = 120
+)
+
+// camera is the view of the field. The position is the top-left of the screen in the field in pixels.
+//
+// The camera follows the gopher and is clamped to the field bounds. Dragging pans the camera.
+// A press on a target of taps is a tap and never pans the camera.
+type camera struct {
+	x int
+	y int
+
+	pressed  bool
+	dragging bool
+	onTarget bool
+	pressX   int
+	pressY   int
+	lastX    int
+	lastY    int
+
+	// panning is the number of ticks left before following the gopher again.
+	panning int
+}
+
+// offset returns the position of the camera to convert positions in the field to the screen.
+func (c *camera) offset() image.Point {
+	return image.Pt(c.x, c.y)
+}
+
+// update moves the camera by dragging or toward the gopher.
+func (c *camera) update(input scene.Input, s *sim.Simulation) {
+	x, y := input.CursorPosition()
+	justPressed := false
+	switch {
+	case !input.IsPressed():
+		c.pressed = false
+		c.dragging = false
+	case !c.pressed:
+		c.pressed = true
+		c.pressX, c.pressY = x, y
+		justPressed = true
+	case c.onTarget:
+	case !c.dragging:
+		if abs(x-c.pressX) >= dragThreshold || abs(y-c.pressY) >= dragThreshold {
+			c.dragging = true
+			c.x -= x - c.pressX
+			c.y -= y - c.pressY
+		}
+	default:
+		c.x -= x - c.lastX
+		c.y -= y - c.lastY
+	}
+	c.lastX, c.lastY = x, y
+
+	if c.dragging {
+		c.panning = panDuration
+	} else if c.panning > 0 {
+		c.panning--
+	} else {
+		c.follow(s)
+	}
+	c.clamp(s.Field())
+
+	// The game scene converts the press with the camera position after moving.
+	if justPressed {
+		c.onTarget = isOnTarget(s, image.Pt(x+c.x, y+c.y))
+	}
+}
+
+// follow centers the camera on the gopher.
+func (c *camera) follow(s *sim.Simulation) {
+	a := s.Player().ConflictionArea()
+	c.x = (a.Min.X+a.Max.X)/2 - viewWidth/2
+	c.y = (a.Min.Y+a.Max.Y)/2 - viewHeight/2
+	c.clamp(s.Field())
+}
+
+// clamp keeps the camera in the field. A field smaller than the screen is at the top-left.
+func (c *camera) clamp(f *sim.Field) {
+	w, h := f.Size()
+	if maxX := w*tileWidth - viewWidth; c.x > maxX {
+		c.x = maxX
+	}
+	if maxY := h*tileHeight - viewHeight; c.y > maxY {
+		c.y = maxY
+	}
+	if c.x < 0 {
+		c.x = 0
+	}
+	if c.y < 0 {
+		c.y = 0
+	}
+}
+
+// isOnTarget reports whether p in the field is in any target of taps.
+func isOnTarget(s *sim.Simulation, p image.Point) bool {
+	for _, t := range s.Targets() {
+		if p.In(t) {
+			return true
+		}
+	}
+	return false
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// worldInput is an input whose positions are converted from the screen to the field.
+type worldInput struct {
+	scene.Input
+	offset image.Point
+}
+
+func (w *worldInput) CursorPosition() (x, y int) {
+	x, y = w.Input.CursorPosition()
+	return x + w.offset.X, y + w.offset.Y
+}
+
+func (w *worldInput) JustStartedTouches() []scene.Touch {
+	var ts []scene.Touch
+	for _, t := range w.Input.JustStartedTouches() {
+		t.X += w.offset.X
+		t.Y += w.offset.Y
+		ts = append(ts, t)
+	}
+	return ts
+}
@@ -0,0 +1,107 @@
+// Copyright 2019 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gamescene
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten"
+
+	"github.com/hajimehoshi/gopherwalk/internal/scene"
+	"github.com/hajimehoshi/gopherwalk/internal/sim"
+	"github.com/hajimehoshi/gopherwalk/internal/ui"
+)
+
+// virtualCursor is the cursor for keyboards and gamepads. It moves between the objects that react to taps.
+type virtualCursor struct {
+	// index is the index of the target in Simulation.Targets.
+	index   int
+	visible bool
+}
+
+// update moves the cursor by the direction actions.
+// If the confirm action is pressed, update returns an input that taps the target. Otherwise, update returns input as it is.
+func (c *virtualCursor) update(input scene.Input, s *sim.Simulation) scene.Input {
+	if input.IsJustTapped() {
+		c.visible = false
+		return input
+	}
+
+	targets := s.Targets()
+	if !c.visible {
+		// The cursor appears on the gopher.
+		c.index = len(targets) - 1
+	}
+	if i, ok := ui.NearestAction(input, targets, c.index); ok {
+		if c.visible {
+			c.index = i
+		}
+		c.visible = true
+		return input
+	}
+	if !input.IsActionJustPressed(scene.ActionConfirm) {
+		return input
+	}
+	if !c.visible {
+		c.visible = true
+		return input
+	}
+	x, y := sim.TapPoint(targets, c.index)
+	return &virtualTap{
+		Input: input,
+		x:     x,
+		y:     y,
+	}
+}
+
+func (c *virtualCursor) draw(screen *ebiten.Image, s *sim.Simulation, offset image.Point) {
+	if !c.visible {
+		return
+	}
+	ui.DrawFrame(screen, s.Targets()[c.index].Sub(offset), color.NRGBA{0xff, 0xff, 0xff, 0xff})
+}
+
+// virtualTouchID is the touch ID of virtualTap.
+const virtualTouchID = -2
+
+// virtualTap is an input that taps at (x, y).
+type virtualTap struct {
+	scene.Input
+	x int
+	y int
+}
+
+func (v *virtualTap) CursorPosition() (x, y int) {
+	return v.x, v.y
+}
+
+func (v *virtualTap) IsJustTapped() bool {
+	return true
+}
+
+func (v *virtualTap) IsPressed() bool {
+	return true
+}
+
+func (v *virtualTap) JustStartedTouches() []scene.Touch {
+	return []scene.Touch{
+		{
+			ID: virtualTouchID,
+			X:  v.x,
+			Y:  v.y,
+		},
+	}
+}
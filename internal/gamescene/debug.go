@@ -0,0 +1,92 @@
+// Copyright 2019 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build debug
+// +build debug
+
+package gamescene
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/ebitenutil"
+
+	"github.com/hajimehoshi/gopherwalk/internal/level"
+	"github.com/hajimehoshi/gopherwalk/internal/scene"
+	"github.com/hajimehoshi/gopherwalk/internal/sim"
+)
+
+// The debug overlay is available only with the build tag 'debug'. ActionDebug toggles it.
+
+type bounded interface {
+	Area() image.Rectangle
+}
+
+func (s *GameScene) updateDebug(input scene.Input) {
+	if input.IsActionJustPressed(scene.ActionDebug) {
+		s.debug = !s.debug
+	}
+}
+
+func drawArea(screen *ebiten.Image, a image.Rectangle, clr color.Color) {
+	ebitenutil.DrawRect(screen, float64(a.Min.X), float64(a.Min.Y), float64(a.Dx()), float64(a.Dy()), clr)
+}
+
+func (s *GameScene) drawDebug(screen *ebiten.Image) {
+	if !s.debug {
+		return
+	}
+
+	o := s.camera.offset()
+	grid := color.NRGBA{0, 0, 0, 0x20}
+	for i := 1; i <= level.ScreenWidth; i++ {
+		x := float64(i*tileWidth - o.X%tileWidth)
+		ebitenutil.DrawLine(screen, x, 0, x, viewHeight, grid)
+	}
+	for j := 1; j <= level.ScreenHeight; j++ {
+		y := float64(j*tileHeight - o.Y%tileHeight)
+		ebitenutil.DrawLine(screen, 0, y, viewWidth, y, grid)
+	}
+
+	for _, obj := range s.sim.Field().Objects() {
+		b, ok := obj.(bounded)
+		if !ok {
+			continue
+		}
+		a := b.Area().Sub(o)
+		drawArea(screen, a, color.NRGBA{0, 0xff, 0, 0x20})
+		// The edges that OverlapsWithDir tests for each direction.
+		for _, dir := range []sim.Dir{sim.DirLeft, sim.DirRight, sim.DirUp, sim.DirDown} {
+			drawArea(screen, sim.Edge(a, dir), color.NRGBA{0, 0x80, 0, 0xff})
+		}
+	}
+
+	p := s.sim.Player()
+	drawArea(screen, p.ClickableArea().Sub(o), color.NRGBA{0, 0, 0xff, 0x40})
+	drawArea(screen, p.ConflictionArea().Sub(o), color.NRGBA{0, 0, 0xff, 0x40})
+	drawArea(screen, p.ElevatorArea().Sub(o), color.NRGBA{0, 0, 0xff, 0xff})
+	drawArea(screen, p.FootArea().Sub(o), color.NRGBA{0, 0, 0xff, 0x80})
+
+	dir := "left"
+	if p.Dir() == sim.DirRight {
+		dir = "right"
+	}
+	x32, y32 := p.Position()
+	msg := fmt.Sprintf("tick: %d\nx32: %d, y32: %d\ndir: %s\nstate: %s\ncamera: %d, %d",
+		s.sim.Tick(), x32, y32, dir, p.State(), o.X, o.Y)
+	ebitenutil.DebugPrintAt(screen, msg, 4, 4)
+}
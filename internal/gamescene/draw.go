@@ -0,0 +1,178 @@
+// Copyright 2019 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gamescene
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"unicode"
+
+	"github.com/hajimehoshi/bitmapfont"
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/text"
+
+	"github.com/hajimehoshi/gopherwalk/internal/sim"
+	"github.com/hajimehoshi/gopherwalk/internal/sprite"
+)
+
+const (
+	tileWidth  = sim.TileWidth
+	tileHeight = sim.TileHeight
+)
+
+// drawSprite draws img at (x, y) in tiles. offset is the camera position in pixels.
+func drawSprite(screen *ebiten.Image, img *ebiten.Image, x, y int, offset image.Point) {
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(float64(x*tileWidth-offset.X), float64(y*tileHeight-offset.Y))
+	screen.DrawImage(img, op)
+}
+
+// drawLetter draws a letter at the bottom-right of the tile at (x, y). offset is the camera position in pixels.
+func drawLetter(screen *ebiten.Image, letter rune, x, y int, offset image.Point) {
+	text.Draw(screen, string(letter), bitmapfont.Gothic12r, x*tileWidth+tileWidth-6-offset.X, y*tileHeight+tileHeight-1-offset.Y, color.White)
+}
+
+// drawField draws the objects of the field. offset is the camera position in pixels.
+func drawField(screen *ebiten.Image, f *sim.Field, tick int, offset image.Point) {
+	for _, o := range f.Objects() {
+		drawObject(screen, o, tick, offset)
+	}
+}
+
+// drawObject draws the object. offset is the camera position in pixels.
+func drawObject(screen *ebiten.Image, o sim.Object, tick int, offset image.Point) {
+	x, y := o.Position()
+	switch o := o.(type) {
+	case *sim.ObjectWall:
+		if o.Big() {
+			drawSprite(screen, sprite.Image("bigwall"), x, y, offset)
+			return
+		}
+		drawSprite(screen, sprite.Image(fmt.Sprintf("wall_%d", o.Neighbors())), x, y, offset)
+	case *sim.ObjectFF:
+		name := "ff"
+		if o.Big() {
+			name = "bigff"
+		}
+		if o.Channel() != 0 {
+			name = "ff_linked"
+		}
+		if o.On() {
+			name += "_on"
+		} else {
+			name += "_off"
+		}
+		drawSprite(screen, sprite.Image(name), x, y, offset)
+		if o.Channel() != 0 {
+			drawLetter(screen, unicode.ToUpper(o.Channel()), x, y, offset)
+		}
+	case *sim.ObjectElevator:
+		name := "elevator"
+		if o.Toggleable() {
+			if o.Down() {
+				name = "elevator_toggle_down"
+			} else {
+				name = "elevator_toggle_up"
+			}
+		} else if o.Down() {
+			name = "elevator_down"
+		}
+		for j := 0; j < o.Height(); j++ {
+			drawSprite(screen, sprite.AnimationImage(name, tick), x, y+j, offset)
+		}
+	case *sim.ObjectGoal:
+		drawSprite(screen, sprite.AnimationImage("goal", tick), x, y, offset)
+	case *sim.ObjectKey:
+		if o.Collected() {
+			return
+		}
+		drawSprite(screen, sprite.Image("key_"+o.Color().String()), x, y, offset)
+	case *sim.ObjectDoor:
+		name := "door_" + o.Color().String()
+		if o.Open() {
+			name += "_open"
+		}
+		drawSprite(screen, sprite.Image(name), x, y, offset)
+	case *sim.ObjectSwitch:
+		name := "switch_momentary"
+		if o.Channel().Latching {
+			name = "switch_latching"
+		}
+		if o.Pressed() {
+			name += "_down"
+		} else {
+			name += "_up"
+		}
+		drawSprite(screen, sprite.Image(name), x, y, offset)
+		drawLetter(screen, o.Channel().Letter, x, y, offset)
+	case *sim.ObjectHazard:
+		if o.Lava() {
+			drawSprite(screen, sprite.AnimationImage("lava", tick), x, y, offset)
+			return
+		}
+		drawSprite(screen, sprite.Image("spikes"), x, y, offset)
+	case *sim.ObjectTeleporter:
+		drawSprite(screen, sprite.AnimationImage("teleporter", tick), x, y, offset)
+		drawLetter(screen, o.Digit(), x, y, offset)
+	default:
+		panic(fmt.Sprintf("gamescene: unexpected object %T", o))
+	}
+}
+
+// drawPlayer draws the player. offset is the camera position in pixels.
+func drawPlayer(screen *ebiten.Image, p *sim.Player, offset image.Point) {
+	if p.Dead() {
+		drawDeadPlayer(screen, p, offset)
+		return
+	}
+
+	x32, y32 := p.Position()
+	var img *ebiten.Image
+	switch s := p.State(); {
+	case s == sim.PlayerStateAtGoal:
+		img = sprite.Image("gopher_goal_0")
+	case s == sim.PlayerStateFalling:
+		img = sprite.Image("gopher_fall_0")
+	case s == sim.PlayerStateClimbing:
+		img = sprite.AnimationImage("gopher_climb", -y32)
+	case s == sim.PlayerStateDescending:
+		img = sprite.AnimationImage("gopher_climb", y32)
+	case p.Turning():
+		img = sprite.Image("gopher_turn_0")
+	default:
+		// The walking animation follows the position so that the feet don't slip.
+		img = sprite.AnimationImage("gopher_walk", x32)
+	}
+
+	// The sprites face left.
+	op := &ebiten.DrawImageOptions{}
+	if p.Dir() == sim.DirRight {
+		op.GeoM.Scale(-1, 1)
+		op.GeoM.Translate(tileWidth, 0)
+	}
+	a := p.ConflictionArea().Sub(offset)
+	op.GeoM.Translate(float64(a.Min.X), float64(a.Min.Y))
+	screen.DrawImage(img, op)
+}
+
+// drawDeadPlayer draws the dying gopher that jumps and falls off the screen.
+func drawDeadPlayer(screen *ebiten.Image, p *sim.Player, offset image.Point) {
+	t := p.Dying()
+	op := &ebiten.DrawImageOptions{}
+	a := p.ConflictionArea().Sub(offset)
+	op.GeoM.Translate(float64(a.Min.X), float64(a.Min.Y+t*t/16-3*t/2))
+	screen.DrawImage(sprite.Image("gopher_dead_0"), op)
+}
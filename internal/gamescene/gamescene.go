@@ -15,45 +15,236 @@
 package gamescene
 
 import (
+	"fmt"
+	"image"
 	"image/color"
+	"strings"
 
+	"github.com/hajimehoshi/bitmapfont"
 	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/text"
 
+	"github.com/hajimehoshi/gopherwalk/internal/level"
 	"github.com/hajimehoshi/gopherwalk/internal/scene"
+	"github.com/hajimehoshi/gopherwalk/internal/sim"
+	"github.com/hajimehoshi/gopherwalk/internal/sprite"
+	"github.com/hajimehoshi/gopherwalk/internal/ui"
 )
 
-func New(id int) *GameScene {
-	f := strToField(testFields[id])
+// New creates a game scene of the field.
+// id is the ID of the field in the pack, or 0 if the field is not in the pack, e.g. test play in the editor.
+// lv is nil when the field of id is not found.
+func New(id int, lv *level.Level) (*GameScene, error) {
+	if lv == nil {
+		return nil, fmt.Errorf("gamescene: field %d not found", id)
+	}
+	if !strings.ContainsRune(lv.Field, level.GlyphStart) {
+		return nil, fmt.Errorf("gamescene: field %d has no start", id)
+	}
+	return newGameScene(id, lv, &sim.Replay{FileName: lv.FileName}, nil), nil
+}
 
-	x, y := f.StartPosition()
-	p := NewPlayer(x, y)
+// NewPlayback creates a game scene of the field of id that plays back the replay instead of the user's input.
+func NewPlayback(id int, lv *level.Level, replay *sim.Replay) *GameScene {
+	return newGameScene(id, lv, replay, sim.NewPlaybackInput(replay.Taps))
+}
 
-	return &GameScene{
-		id:     id,
-		player: p,
-		field:  f,
+func newGameScene(id int, lv *level.Level, replay *sim.Replay, playback *sim.PlaybackInput) *GameScene {
+	s := &GameScene{
+		id:       id,
+		level:    lv,
+		sim:      sim.New(lv),
+		replay:   replay,
+		playback: playback,
 	}
+	s.menuButton = ui.NewButton(image.Rect(0, 0, 32, 16), "Menu")
+	s.menuButton.SetOnTap(func() {
+		s.pausing = true
+	})
+	s.camera.follow(s.sim)
+	return s
 }
 
 type GameScene struct {
-	id     int
-	player *Player
-	field  *Field
+	id       int
+	level    *level.Level
+	sim      *sim.Simulation
+	replay   *sim.Replay
+	playback *sim.PlaybackInput
+
+	// history is the snapshots for rewinding.
+	history   history
+	rewinding bool
+
+	menuButton *ui.Button
+
+	// pausing indicates whether the menu button is tapped.
+	pausing bool
+
+	cursor virtualCursor
+	camera camera
+
+	// debug indicates whether the debug overlay is shown.
+	debug bool
+}
+
+// Replay returns the record of the taps so far.
+func (s *GameScene) Replay() *sim.Replay {
+	return s.replay
+}
+
+func (s *GameScene) AtGoal() bool {
+	return s.sim.AtGoal()
+}
+
+// rewind restores the state of the previous tick and forgets the taps after it.
+func (s *GameScene) rewind() {
+	ss := s.history.pop()
+	if ss == nil {
+		return
+	}
+	s.sim.Restore(ss)
+	taps := s.replay.Taps
+	for len(taps) > 0 && taps[len(taps)-1].Tick >= ss.Tick() {
+		taps = taps[:len(taps)-1]
+	}
+	s.replay.Taps = taps
+}
+
+// restart restarts the game from the beginning.
+func (s *GameScene) restart() {
+	s.sim = sim.New(s.level)
+	s.history = history{}
+	if s.playback != nil {
+		s.playback = sim.NewPlaybackInput(s.replay.Taps)
+		return
+	}
+	s.replay = &sim.Replay{
+		FileName: s.level.FileName,
+	}
 }
 
 func (s *GameScene) Update(context scene.Context) error {
-	s.field.Update(context)
-	s.player.Update(context, s.field)
+	input := context.Input()
+	s.updateDebug(input)
 
-	if s.player.AtGoal() {
-		context.GoToGameScene(s.id + 1)
+	s.menuButton.Update(input)
+	if s.pausing || input.IsActionJustPressed(scene.ActionCancel) {
+		s.pausing = false
+		context.PushScene(newPauseMenu(s))
+		return nil
+	}
+	if input.IsActionJustPressed(scene.ActionRestart) {
+		s.restart()
+	}
+
+	s.camera.update(input, s.sim)
+
+	// Holding the rewind action rewinds the game tick by tick. Releasing it resumes the game from there.
+	s.rewinding = s.playback == nil && input.IsActionPressed(scene.ActionRewind)
+	if s.rewinding {
+		s.rewind()
+		return nil
+	}
+	s.history.push(s.sim.Snapshot())
+
+	var taps sim.Input
+	if s.playback != nil {
+		s.playback.SetTick(s.sim.Tick())
+		taps = s.playback
+	} else {
+		// Taps are converted to the positions in the field.
+		input = &worldInput{
+			Input:  input,
+			offset: s.camera.offset(),
+		}
+		input = s.cursor.update(input, s.sim)
+		var ts sim.TapInput
+		for _, t := range input.JustStartedTouches() {
+			// A press out of the targets doesn't affect the game and might be a drag to pan the camera.
+			// Don't count it as a tap.
+			if !isOnTarget(s.sim, image.Pt(t.X, t.Y)) {
+				continue
+			}
+			s.replay.Taps = append(s.replay.Taps, sim.Tap{
+				Tick: s.sim.Tick(),
+				X:    t.X,
+				Y:    t.Y,
+			})
+			ts = append(ts, image.Pt(t.X, t.Y))
+		}
+		taps = ts
+	}
+	s.sim.Update(taps)
+
+	// Offer a retry once the dying animation finishes.
+	if p := s.sim.Player(); p.Dead() && p.Dying() == sim.DyingDuration {
+		context.PushScene(newRetryMenu(s))
+		return nil
+	}
+
+	if s.sim.AtGoal() && s.id != 0 {
+		context.GoToResultScene(&scene.Result{
+			FieldID:  s.id,
+			Ticks:    s.sim.Tick(),
+			Taps:     len(s.replay.Taps),
+			Toggles:  s.sim.Toggles(),
+			Turns:    s.sim.Player().Turns(),
+			Replayed: s.playback != nil,
+		})
 	}
 
 	return nil
 }
 
+// FocusPoint implements scene.Focused. FocusPoint returns the center of the gopher on the screen.
+func (s *GameScene) FocusPoint() (x, y int) {
+	a := s.sim.Player().ConflictionArea().Sub(s.camera.offset())
+	return (a.Min.X + a.Max.X) / 2, (a.Min.Y + a.Max.Y) / 2
+}
+
 func (s *GameScene) Draw(screen *ebiten.Image) {
+	draw(screen, s.sim.Field(), s.sim.Player(), s.sim.Tick(), s.camera.offset())
+	if s.rewinding {
+		text.Draw(screen, "<< Rewind", bitmapfont.Gothic12r, 40, 12, color.Black)
+	}
+	s.cursor.draw(screen, s.sim, s.camera.offset())
+	s.drawKeys(screen)
+	s.drawDebug(screen)
+	s.menuButton.Draw(screen)
+}
+
+// drawKeys draws the collected keys at the top-right corner of the screen, below the speed bar in the first row.
+func (s *GameScene) drawKeys(screen *ebiten.Image) {
+	x := viewWidth
+	for i := len(sim.KeyColors) - 1; i >= 0; i-- {
+		c := sim.KeyColors[i]
+		if !s.sim.Player().HasKey(c) {
+			continue
+		}
+		x -= tileWidth
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Translate(float64(x), tileHeight)
+		screen.DrawImage(sprite.Image("key_"+c.String()), op)
+	}
+}
+
+// DrawLevel draws the field as it looks at the start of a game.
+// The field doesn't have to have a start or a goal.
+func DrawLevel(screen *ebiten.Image, lv *level.Level) {
+	sm := sim.New(lv)
+	p := sm.Player()
+	if !strings.ContainsRune(lv.Field, level.GlyphStart) {
+		p = nil
+	}
+	draw(screen, sm.Field(), p, 0, image.Point{})
+}
+
+// draw draws the field and the player. offset is the camera position in pixels.
+func draw(screen *ebiten.Image, f *sim.Field, p *sim.Player, tick int, offset image.Point) {
 	screen.Fill(color.NRGBA{0x99, 0xcc, 0xff, 0xff})
-	s.field.Draw(screen)
-	s.player.Draw(screen)
+	drawField(screen, f, tick, offset)
+	if p != nil {
+		drawPlayer(screen, p, offset)
+	}
 }
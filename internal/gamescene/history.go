@@ -0,0 +1,55 @@
+// Copyright 2019 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gamescene
+
+import (
+	"github.com/hajimehoshi/gopherwalk/internal/sim"
+)
+
+// rewindTicks is the maximum number of ticks that can be rewound.
+const rewindTicks = 60 * 10
+
+// history is a ring buffer of the snapshots of the recent ticks.
+type history struct {
+	snapshots []*sim.Snapshot
+	start     int
+	len       int
+}
+
+func (h *history) push(ss *sim.Snapshot) {
+	if h.snapshots == nil {
+		h.snapshots = make([]*sim.Snapshot, rewindTicks)
+	}
+	if h.len < len(h.snapshots) {
+		h.snapshots[(h.start+h.len)%len(h.snapshots)] = ss
+		h.len++
+		return
+	}
+	// Overwrite the oldest one.
+	h.snapshots[h.start] = ss
+	h.start = (h.start + 1) % len(h.snapshots)
+}
+
+// pop removes and returns the latest snapshot. pop returns nil if the history is empty.
+func (h *history) pop() *sim.Snapshot {
+	if h.len == 0 {
+		return nil
+	}
+	h.len--
+	i := (h.start + h.len) % len(h.snapshots)
+	ss := h.snapshots[i]
+	h.snapshots[i] = nil
+	return ss
+}
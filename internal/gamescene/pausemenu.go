@@ -0,0 +1,136 @@
+// Copyright 2019 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gamescene
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/ebitenutil"
+
+	"github.com/hajimehoshi/gopherwalk/internal/level"
+	"github.com/hajimehoshi/gopherwalk/internal/scene"
+	"github.com/hajimehoshi/gopherwalk/internal/settingsscene"
+	"github.com/hajimehoshi/gopherwalk/internal/ui"
+)
+
+// pauseMenu is a menu pushed over the game. The game is suspended while the menu is open.
+type pauseMenu struct {
+	buttons []*ui.Button
+	focus   ui.Focus
+
+	// action is the action of the tapped button, executed after updating the buttons.
+	action func(context scene.Context)
+
+	// cancel is executed on the cancel action.
+	cancel func(context scene.Context)
+}
+
+type menuItem struct {
+	text   string
+	action func(context scene.Context)
+}
+
+func newMenu(items []menuItem, cancel func(context scene.Context)) *pauseMenu {
+	m := &pauseMenu{
+		cancel: cancel,
+	}
+	const (
+		w = 96
+		h = 16
+		x = (level.ScreenWidth*tileWidth - w) / 2
+	)
+	y := 64
+	for _, b := range items {
+		b := b
+		btn := ui.NewButton(image.Rect(x, y, x+w, y+h), b.text)
+		btn.SetOnTap(func() {
+			m.action = b.action
+		})
+		m.buttons = append(m.buttons, btn)
+		y += h + 8
+	}
+	return m
+}
+
+func newPauseMenu(s *GameScene) *pauseMenu {
+	items := []menuItem{
+		{"Resume", func(context scene.Context) {
+			context.PopScene()
+		}},
+		{"Restart", func(context scene.Context) {
+			s.restart()
+			context.PopScene()
+		}},
+	}
+	// Test play in the editor (id 0) goes back to the editor by Esc, and the menu doesn't leave the editor.
+	if s.id != 0 {
+		items = append(items,
+			menuItem{"Level Select", func(context scene.Context) {
+				context.GoToFieldSelectorScene()
+			}},
+			menuItem{"Settings", func(context scene.Context) {
+				context.PushScene(settingsscene.New(context.Settings(), func(context scene.Context) {
+					context.PopScene()
+				}))
+			}})
+	}
+	return newMenu(items, func(context scene.Context) {
+		context.PopScene()
+	})
+}
+
+// newRetryMenu creates a menu shown after the gopher dies. The game can't be resumed.
+func newRetryMenu(s *GameScene) *pauseMenu {
+	retry := func(context scene.Context) {
+		s.restart()
+		context.PopScene()
+	}
+	items := []menuItem{
+		{"Retry", retry},
+	}
+	if s.id != 0 {
+		items = append(items, menuItem{"Level Select", func(context scene.Context) {
+			context.GoToFieldSelectorScene()
+		}})
+	}
+	return newMenu(items, retry)
+}
+
+func (m *pauseMenu) Update(context scene.Context) error {
+	if context.Input().IsActionJustPressed(scene.ActionCancel) {
+		m.cancel(context)
+		return nil
+	}
+	for _, b := range m.buttons {
+		b.Update(context.Input())
+	}
+	m.focus.Update(context.Input(), m.buttons)
+	if m.action != nil {
+		a := m.action
+		m.action = nil
+		a(context)
+	}
+	return nil
+}
+
+func (m *pauseMenu) Draw(screen *ebiten.Image) {
+	w, h := screen.Size()
+	ebitenutil.DrawRect(screen, 0, 0, float64(w), float64(h), color.NRGBA{0xff, 0xff, 0xff, 0xc0})
+	for _, b := range m.buttons {
+		b.Draw(screen)
+	}
+}
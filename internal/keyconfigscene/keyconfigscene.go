@@ -0,0 +1,261 @@
+// Copyright 2019 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyconfigscene
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+
+	"github.com/hajimehoshi/bitmapfont"
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/text"
+
+	"github.com/hajimehoshi/gopherwalk/internal/binding"
+	"github.com/hajimehoshi/gopherwalk/internal/save"
+	"github.com/hajimehoshi/gopherwalk/internal/scene"
+	"github.com/hajimehoshi/gopherwalk/internal/ui"
+)
+
+const (
+	lineHeight = 14
+	top        = 24
+	labelX     = 8
+	keyX       = 88
+	keyWidth   = 88
+	buttonX    = 184
+	buttonW    = 64
+)
+
+var labels = map[scene.Action]string{
+	scene.ActionUp:      "Up",
+	scene.ActionDown:    "Down",
+	scene.ActionLeft:    "Left",
+	scene.ActionRight:   "Right",
+	scene.ActionConfirm: "Confirm",
+	scene.ActionCancel:  "Cancel",
+	scene.ActionPause:   "Pause",
+	scene.ActionStep:    "Frame step",
+	scene.ActionSlower:  "Slower",
+	scene.ActionFaster:  "Faster",
+	scene.ActionTurbo:   "Turbo",
+	scene.ActionRestart: "Restart",
+	scene.ActionRewind:  "Rewind",
+	scene.ActionDebug:   "Debug",
+}
+
+// waiting is the binding waiting for a key or a gamepad button.
+type waiting struct {
+	action  scene.Action
+	gamepad bool
+}
+
+// KeyConfigScene is a scene to bind keys and gamepad buttons to actions.
+//
+// Tapping a key or gamepad button of an action waits for a key or a gamepad button to replace the binding.
+// The user cannot leave the scene while an input is bound to multiple actions.
+type KeyConfigScene struct {
+	settings *save.Settings
+	back     func(context scene.Context)
+	bindings binding.Table
+
+	// keyButtons and padButtons are the buttons of the actions in the order of binding.Actions.
+	keyButtons []*ui.Button
+	padButtons []*ui.Button
+	buttons    []*ui.Button
+	focus      ui.Focus
+
+	waiting   *waiting
+	conflicts map[scene.Action]bool
+
+	// dirty indicates whether the bindings are changed.
+	dirty  bool
+	closed bool
+}
+
+// New creates a key config scene that edits the bindings in settings. back is called when the user leaves the scene.
+func New(settings *save.Settings, back func(context scene.Context)) *KeyConfigScene {
+	s := &KeyConfigScene{
+		settings: settings,
+		back:     back,
+		bindings: binding.FromSettings(settings),
+	}
+
+	for i, a := range binding.Actions {
+		a := a
+		y := top + i*lineHeight
+		kb := ui.NewButton(image.Rect(keyX, y, keyX+keyWidth, y+lineHeight), "")
+		kb.SetOnTap(func() {
+			s.waiting = &waiting{action: a}
+		})
+		s.keyButtons = append(s.keyButtons, kb)
+
+		pb := ui.NewButton(image.Rect(buttonX, y, buttonX+buttonW, y+lineHeight), "")
+		pb.SetOnTap(func() {
+			s.waiting = &waiting{action: a, gamepad: true}
+		})
+		s.padButtons = append(s.padButtons, pb)
+		s.buttons = append(s.buttons, kb, pb)
+	}
+
+	y := top + len(binding.Actions)*lineHeight + 4
+	reset := ui.NewButton(image.Rect(labelX, y, labelX+48, y+16), "Reset")
+	reset.SetOnTap(func() {
+		s.bindings = binding.Default()
+		s.dirty = true
+	})
+	b := ui.NewButton(image.Rect(labelX+56, y, labelX+104, y+16), "Back")
+	b.SetOnTap(func() {
+		s.closed = true
+	})
+	s.buttons = append(s.buttons, reset, b)
+
+	s.updateTexts()
+	return s
+}
+
+func (s *KeyConfigScene) updateTexts() {
+	for i, a := range binding.Actions {
+		var keys, buttons []string
+		if b, ok := s.bindings[a]; ok {
+			for _, k := range b.Keys {
+				keys = append(keys, k.String())
+			}
+			for _, gb := range b.GamepadButtons {
+				buttons = append(buttons, fmt.Sprintf("%d", gb))
+			}
+		}
+
+		kt := strings.Join(keys, "/")
+		if kt == "" {
+			kt = "-"
+		}
+		pt := "-"
+		if len(buttons) > 0 {
+			pt = "Pad " + strings.Join(buttons, "/")
+		}
+		if s.waiting != nil && s.waiting.action == a {
+			if s.waiting.gamepad {
+				pt = "Press..."
+			} else {
+				kt = "Press..."
+			}
+		}
+		s.keyButtons[i].SetText(kt)
+		s.padButtons[i].SetText(pt)
+	}
+	s.conflicts = s.bindings.Conflicts()
+}
+
+// IsCapturing implements scene.Capturer.
+func (s *KeyConfigScene) IsCapturing() bool {
+	return s.waiting != nil
+}
+
+// capture binds the key or the gamepad button pressed at the current tick to the waiting action.
+// A tap cancels the waiting, and so does a key while waiting for a gamepad button.
+func (s *KeyConfigScene) capture(input scene.Input) {
+	if input.IsJustTapped() {
+		s.waiting = nil
+		return
+	}
+
+	b, ok := s.bindings[s.waiting.action]
+	if !ok {
+		b = &binding.Binding{}
+		s.bindings[s.waiting.action] = b
+	}
+	if s.waiting.gamepad {
+		for gb := ebiten.GamepadButton(0); gb <= ebiten.GamepadButtonMax; gb++ {
+			if input.IsGamepadButtonJustPressed(gb) {
+				b.GamepadButtons = []ebiten.GamepadButton{gb}
+				s.waiting = nil
+				s.dirty = true
+				return
+			}
+		}
+		for k := ebiten.Key(0); k <= ebiten.KeyMax; k++ {
+			if input.IsKeyJustPressed(k) {
+				s.waiting = nil
+				return
+			}
+		}
+		return
+	}
+	for k := ebiten.Key(0); k <= ebiten.KeyMax; k++ {
+		if input.IsKeyJustPressed(k) {
+			b.Keys = []ebiten.Key{k}
+			s.waiting = nil
+			s.dirty = true
+			return
+		}
+	}
+}
+
+func (s *KeyConfigScene) Update(context scene.Context) error {
+	if s.waiting != nil {
+		s.capture(context.Input())
+		s.updateTexts()
+		return nil
+	}
+
+	for _, b := range s.buttons {
+		b.Update(context.Input())
+	}
+	s.focus.Update(context.Input(), s.buttons)
+	s.updateTexts()
+
+	if context.Input().IsActionJustPressed(scene.ActionCancel) {
+		s.closed = true
+	}
+	if !s.closed {
+		return nil
+	}
+	s.closed = false
+	if len(s.conflicts) > 0 {
+		return nil
+	}
+	if s.dirty {
+		s.bindings.Save(s.settings)
+		if err := context.SaveSettings(); err != nil {
+			return err
+		}
+		s.dirty = false
+	}
+	s.back(context)
+	return nil
+}
+
+func (s *KeyConfigScene) Draw(screen *ebiten.Image) {
+	screen.Fill(color.White)
+	text.Draw(screen, "Key Config", bitmapfont.Gothic12r, labelX, 4+12, color.Black)
+	text.Draw(screen, "Key", bitmapfont.Gothic12r, keyX+4, 4+12, color.Black)
+	text.Draw(screen, "Gamepad", bitmapfont.Gothic12r, buttonX+4, 4+12, color.Black)
+	for i, a := range binding.Actions {
+		var clr color.Color = color.Black
+		if s.conflicts[a] {
+			clr = color.NRGBA{0xff, 0, 0, 0xff}
+		}
+		text.Draw(screen, labels[a], bitmapfont.Gothic12r, labelX, top+i*lineHeight+12, clr)
+	}
+	if len(s.conflicts) > 0 {
+		y := top + len(binding.Actions)*lineHeight + 4
+		text.Draw(screen, "Conflicting bindings", bitmapfont.Gothic12r, labelX+112, y+12, color.NRGBA{0xff, 0, 0, 0xff})
+	}
+	for _, b := range s.buttons {
+		b.Draw(screen)
+	}
+}
@@ -0,0 +1,330 @@
+// Copyright 2019 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package level
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// The size of a screen in tiles.
+const (
+	ScreenWidth  = 16
+	ScreenHeight = 15
+)
+
+// The maximum size of a field in tiles. Fields larger than a screen are scrolled.
+const (
+	MaxWidth  = 128
+	MaxHeight = 128
+)
+
+const (
+	GlyphEmpty         = ' '
+	GlyphFiller        = '.'
+	GlyphWall          = 'w'
+	GlyphBigWall       = 'W'
+	GlyphForceField    = 'f'
+	GlyphBigForceField = 'F'
+	GlyphElevator      = 'e'
+	GlyphStart         = 's'
+	GlyphGoal          = 'g'
+
+	// GlyphDownElevator is an elevator that carries the gopher down.
+	// GlyphToggleElevator is an elevator that starts going up and flips its direction when tapped.
+	GlyphDownElevator   = 'v'
+	GlyphToggleElevator = 't'
+
+	// A key opens the doors of the same colour when the gopher collects it.
+	GlyphRedKey     = 'r'
+	GlyphRedDoor    = 'R'
+	GlyphBlueKey    = 'b'
+	GlyphBlueDoor   = 'B'
+	GlyphYellowKey  = 'y'
+	GlyphYellowDoor = 'Y'
+
+	// Hazards kill the gopher.
+	GlyphSpikes = '^'
+	GlyphLava   = '~'
+)
+
+// ChannelLetters is the letters of the channels of switches.
+// A lower case letter is a switch of the channel, and an upper case letter is a force field linked to the channel.
+const ChannelLetters = "hijk"
+
+// TeleporterDigits is the glyphs of teleporters. The two teleporters of the same digit are linked.
+const TeleporterDigits = "0123456789"
+
+// IsTeleporter reports whether the glyph is a teleporter.
+func IsTeleporter(glyph rune) bool {
+	return strings.ContainsRune(TeleporterDigits, glyph)
+}
+
+// SwitchChannel returns the channel of the glyph if the glyph is a switch.
+func SwitchChannel(glyph rune) (rune, bool) {
+	if !strings.ContainsRune(ChannelLetters, glyph) {
+		return 0, false
+	}
+	return glyph, true
+}
+
+// LinkedForceFieldChannel returns the channel of the glyph if the glyph is a force field linked to a channel.
+func LinkedForceFieldChannel(glyph rune) (rune, bool) {
+	c := unicode.ToLower(glyph)
+	if c == glyph || !strings.ContainsRune(ChannelLetters, c) {
+		return 0, false
+	}
+	return c, true
+}
+
+var glyphNames = map[rune]string{
+	GlyphEmpty:          "space",
+	GlyphFiller:         "filler",
+	GlyphWall:           "wall",
+	GlyphBigWall:        "big wall",
+	GlyphForceField:     "force field",
+	GlyphBigForceField:  "big force field",
+	GlyphElevator:       "elevator",
+	GlyphDownElevator:   "down elevator",
+	GlyphToggleElevator: "toggle elevator",
+	GlyphStart:          "start",
+	GlyphGoal:           "goal",
+	GlyphRedKey:         "red key",
+	GlyphRedDoor:        "red door",
+	GlyphBlueKey:        "blue key",
+	GlyphBlueDoor:       "blue door",
+	GlyphYellowKey:      "yellow key",
+	GlyphYellowDoor:     "yellow door",
+	GlyphSpikes:         "spikes",
+	GlyphLava:           "lava",
+}
+
+func init() {
+	for _, c := range ChannelLetters {
+		glyphNames[c] = fmt.Sprintf("switch %c", c)
+		glyphNames[unicode.ToUpper(c)] = fmt.Sprintf("force field %c", unicode.ToUpper(c))
+	}
+	for _, c := range TeleporterDigits {
+		glyphNames[c] = fmt.Sprintf("teleporter %c", c)
+	}
+}
+
+// doorKeys maps door glyphs to the glyphs of their keys.
+var doorKeys = map[rune]rune{
+	GlyphRedDoor:    GlyphRedKey,
+	GlyphBlueDoor:   GlyphBlueKey,
+	GlyphYellowDoor: GlyphYellowKey,
+}
+
+// IsBig reports whether the glyph occupies 2x2 tiles.
+// The other three tiles must be filled with GlyphFiller.
+func IsBig(glyph rune) bool {
+	return glyph == GlyphBigWall || glyph == GlyphBigForceField
+}
+
+type Error struct {
+	Name string
+
+	// Line and Col are 1-based. 0 means unknown.
+	Line int
+	Col  int
+
+	Msg string
+}
+
+func (e *Error) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("%s: %s", e.Name, e.Msg)
+	}
+	if e.Col == 0 {
+		return fmt.Sprintf("%s:%d: %s", e.Name, e.Line, e.Msg)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s", e.Name, e.Line, e.Col, e.Msg)
+}
+
+type ErrorList []*Error
+
+func (e ErrorList) Error() string {
+	var strs []string
+	for _, err := range e {
+		strs = append(strs, err.Error())
+	}
+	return strings.Join(strs, "\n")
+}
+
+func (e *ErrorList) add(name string, line, col int, format string, args ...interface{}) {
+	*e = append(*e, &Error{
+		Name: name,
+		Line: line,
+		Col:  col,
+		Msg:  fmt.Sprintf(format, args...),
+	})
+}
+
+// headerPos is the position of a header value in the file. line and col are 1-based.
+type headerPos struct {
+	line int
+	col  int
+}
+
+// validateGrid validates the grid rows.
+// firstLine is the line number of the first row in the file.
+// channels is the positions of the channel letters in the 'channel' headers.
+func validateGrid(name string, rows [][]rune, firstLine int, channels map[rune]headerPos) ErrorList {
+	var errs ErrorList
+
+	if len(rows) == 0 {
+		errs.add(name, 0, 0, "no grid")
+		return errs
+	}
+	if len(rows) > MaxHeight {
+		errs.add(name, firstLine+MaxHeight, 0, "grid is too tall (%d rows > %d)", len(rows), MaxHeight)
+	}
+
+	at := func(x, y int) rune {
+		if y < 0 || len(rows) <= y || x < 0 || len(rows[y]) <= x {
+			return 0
+		}
+		return rows[y][x]
+	}
+
+	type pos struct {
+		x int
+		y int
+	}
+	var start *pos
+	goal := false
+	covered := map[pos]pos{}
+	keys := map[rune]bool{}
+	doors := map[rune]pos{}
+	teleporters := map[rune][]pos{}
+	switches := map[rune]bool{}
+	linkedFFs := map[rune]pos{}
+
+	for j, row := range rows {
+		line := firstLine + j
+		if len(row) != len(rows[0]) {
+			errs.add(name, line, 0, "row has %d columns but the first row has %d", len(row), len(rows[0]))
+		}
+		if len(row) > MaxWidth {
+			errs.add(name, line, MaxWidth+1, "row is too wide (%d columns > %d)", len(row), MaxWidth)
+		}
+
+		for i, c := range row {
+			col := i + 1
+			if _, ok := glyphNames[c]; !ok {
+				errs.add(name, line, col, "unknown glyph %q", c)
+				continue
+			}
+			switch c {
+			case GlyphStart:
+				if start != nil {
+					errs.add(name, line, col, "duplicated start (first at %d:%d)", firstLine+start.y, start.x+1)
+					continue
+				}
+				start = &pos{i, j}
+			case GlyphGoal:
+				goal = true
+			case GlyphRedKey, GlyphBlueKey, GlyphYellowKey:
+				keys[c] = true
+			case GlyphRedDoor, GlyphBlueDoor, GlyphYellowDoor:
+				if _, ok := doors[c]; !ok {
+					doors[c] = pos{i, j}
+				}
+			}
+			if IsTeleporter(c) {
+				teleporters[c] = append(teleporters[c], pos{i, j})
+			}
+			if ch, ok := SwitchChannel(c); ok {
+				switches[ch] = true
+			}
+			if ch, ok := LinkedForceFieldChannel(c); ok {
+				if _, ok := linkedFFs[ch]; !ok {
+					linkedFFs[ch] = pos{i, j}
+				}
+			}
+			if !IsBig(c) {
+				continue
+			}
+			for _, d := range []pos{{1, 0}, {0, 1}, {1, 1}} {
+				p := pos{i + d.x, j + d.y}
+				if at(p.x, p.y) == 0 {
+					errs.add(name, line, col, "%s runs off the grid", glyphNames[c])
+					break
+				}
+				switch at(p.x, p.y) {
+				case GlyphFiller:
+					if o, ok := covered[p]; ok {
+						errs.add(name, line, col, "%s overlaps the %s at %d:%d", glyphNames[c], glyphNames[rows[o.y][o.x]], firstLine+o.y, o.x+1)
+						continue
+					}
+					covered[p] = pos{i, j}
+				default:
+					errs.add(name, line, col, "%s overlaps the %s at %d:%d", glyphNames[c], glyphNames[at(p.x, p.y)], firstLine+p.y, p.x+1)
+				}
+			}
+		}
+	}
+
+	for j, row := range rows {
+		for i, c := range row {
+			if c != GlyphFiller {
+				continue
+			}
+			if _, ok := covered[pos{i, j}]; !ok {
+				errs.add(name, firstLine+j, i+1, "filler is not a part of any big tile")
+			}
+		}
+	}
+
+	for _, d := range []rune{GlyphRedDoor, GlyphBlueDoor, GlyphYellowDoor} {
+		p, ok := doors[d]
+		if !ok || keys[doorKeys[d]] {
+			continue
+		}
+		errs.add(name, firstLine+p.y, p.x+1, "%s without a %s", glyphNames[d], glyphNames[doorKeys[d]])
+	}
+
+	for _, c := range TeleporterDigits {
+		ps, ok := teleporters[c]
+		if !ok || len(ps) == 2 {
+			continue
+		}
+		errs.add(name, firstLine+ps[0].y, ps[0].x+1, "%s has %d pads but must have 2", glyphNames[c], len(ps))
+	}
+
+	for _, c := range ChannelLetters {
+		if switches[c] {
+			continue
+		}
+		if p, ok := linkedFFs[c]; ok {
+			ff := unicode.ToUpper(c)
+			errs.add(name, firstLine+p.y, p.x+1, "%s without a %s", glyphNames[ff], glyphNames[c])
+		}
+		if h, ok := channels[c]; ok {
+			errs.add(name, h.line, h.col, "channel %c without a %s", c, glyphNames[c])
+		}
+	}
+
+	if start == nil {
+		errs.add(name, 0, 0, "no start %q", GlyphStart)
+	}
+	if !goal {
+		errs.add(name, 0, 0, "no goal %q", GlyphGoal)
+	}
+
+	return errs
+}
@@ -0,0 +1,234 @@
+// Copyright 2019 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package level
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateGrid(t *testing.T) {
+	type pos struct {
+		line int
+		col  int
+	}
+	cases := []struct {
+		name     string
+		grid     string
+		channels map[rune]headerPos
+		errs     []pos
+		msgs     []string
+	}{
+		{
+			name: "valid",
+			grid: "w   w\n" +
+				"ws gw\n" +
+				"wwwww",
+		},
+		{
+			name: "no grid",
+			grid: "",
+			errs: []pos{{0, 0}},
+			msgs: []string{"no grid"},
+		},
+		{
+			name: "too tall",
+			grid: strings.Repeat("w\n", MaxHeight) + "s\n" + "g",
+			errs: []pos{{10 + MaxHeight, 0}},
+			msgs: []string{"grid is too tall"},
+		},
+		{
+			name: "ragged row",
+			grid: "sg \n" +
+				"ww",
+			errs: []pos{{11, 0}},
+			msgs: []string{"row has 2 columns but the first row has 3"},
+		},
+		{
+			name: "too wide",
+			grid: "sg" + strings.Repeat(" ", MaxWidth-1),
+			errs: []pos{{10, MaxWidth + 1}},
+			msgs: []string{"row is too wide"},
+		},
+		{
+			name: "unknown glyph",
+			grid: "s?g",
+			errs: []pos{{10, 2}},
+			msgs: []string{"unknown glyph '?'"},
+		},
+		{
+			name: "duplicated start",
+			grid: "s g\n" +
+				" s ",
+			errs: []pos{{11, 2}},
+			msgs: []string{"duplicated start (first at 10:1)"},
+		},
+		{
+			name: "big tile off the grid",
+			grid: "sgW",
+			errs: []pos{{10, 3}},
+			msgs: []string{"big wall runs off the grid"},
+		},
+		{
+			name: "big tiles overlapping",
+			grid: "sW.\n" +
+				"W..\n" +
+				"..g",
+			errs: []pos{{11, 1}},
+			msgs: []string{"big wall overlaps the big wall at 10:2"},
+		},
+		{
+			name: "big tile on another glyph",
+			grid: "sW.\n" +
+				"g.w",
+			errs: []pos{{10, 2}},
+			msgs: []string{"big wall overlaps the wall at 11:3"},
+		},
+		{
+			name: "stray filler",
+			grid: "s.g",
+			errs: []pos{{10, 2}},
+			msgs: []string{"filler is not a part of any big tile"},
+		},
+		{
+			name: "door without a key",
+			grid: "s R g",
+			errs: []pos{{10, 3}},
+			msgs: []string{"red door without a red key"},
+		},
+		{
+			name: "door with a key",
+			grid: "srR g",
+		},
+		{
+			name: "single teleporter",
+			grid: "s 1 g",
+			errs: []pos{{10, 3}},
+			msgs: []string{"teleporter 1 has 1 pads but must have 2"},
+		},
+		{
+			name: "linked force field without a switch",
+			grid: "s H g",
+			errs: []pos{{10, 3}},
+			msgs: []string{"force field H without a switch h"},
+		},
+		{
+			name:     "channel without a switch",
+			grid:     "s g",
+			channels: map[rune]headerPos{'i': {3, 10}},
+			errs:     []pos{{3, 10}},
+			msgs:     []string{"channel i without a switch i"},
+		},
+		{
+			name:     "channel with a switch",
+			grid:     "sHh g",
+			channels: map[rune]headerPos{'h': {3, 10}},
+		},
+		{
+			name: "no start",
+			grid: "  g",
+			errs: []pos{{0, 0}},
+			msgs: []string{"no start 's'"},
+		},
+		{
+			name: "no goal",
+			grid: "s  ",
+			errs: []pos{{0, 0}},
+			msgs: []string{"no goal 'g'"},
+		},
+	}
+	for _, c := range cases {
+		var rows [][]rune
+		if c.grid != "" {
+			for _, line := range strings.Split(c.grid, "\n") {
+				rows = append(rows, []rune(line))
+			}
+		}
+		errs := validateGrid("test.field", rows, 10, c.channels)
+		if len(errs) != len(c.errs) {
+			t.Errorf("%s: got %d errors, want %d: %v", c.name, len(errs), len(c.errs), errs)
+			continue
+		}
+		for i, err := range errs {
+			if got, want := (pos{err.Line, err.Col}), c.errs[i]; got != want {
+				t.Errorf("%s: error %d at %d:%d, want %d:%d", c.name, i, got.line, got.col, want.line, want.col)
+			}
+			if !strings.Contains(err.Msg, c.msgs[i]) {
+				t.Errorf("%s: error %d: got %q, want it to contain %q", c.name, i, err.Msg, c.msgs[i])
+			}
+		}
+	}
+}
+
+func TestParseKeepsBlankRows(t *testing.T) {
+	const data = "name: Blank rows\n" +
+		"\n" +
+		"     \n" +
+		"s   g\n" +
+		"wwwww\n" +
+		"     \n" +
+		"\n"
+	l, err := Parse("test.field", []byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := l.Field, "     \ns   g\nwwwww\n     "; got != want {
+		t.Errorf("Field: got %q, want %q", got, want)
+	}
+}
+
+func TestParseChannelHeader(t *testing.T) {
+	const data = "channel: h close momentary\n" +
+		"channel:  i toggle latching open\n" +
+		"channel: j open latching\n" +
+		"\n" +
+		"s hij g"
+	l, err := Parse("test.field", []byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Channel{
+		{Letter: 'h', Action: SwitchClose, Open: true},
+		{Letter: 'i', Action: SwitchToggle, Latching: true, Open: true},
+		{Letter: 'j', Action: SwitchOpen, Latching: true},
+	}
+	if len(l.Channels) != len(want) {
+		t.Fatalf("Channels: got %v, want %v", l.Channels, want)
+	}
+	for i, c := range l.Channels {
+		if c != want[i] {
+			t.Errorf("Channels[%d]: got %+v, want %+v", i, c, want[i])
+		}
+	}
+
+	l2, err := Parse("test.field", Format(l))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, c := range l2.Channels {
+		if c != want[i] {
+			t.Errorf("Channels[%d] after Format: got %+v, want %+v", i, c, want[i])
+		}
+	}
+
+	_, err = Parse("test.field", []byte("channel: h close momentary\n\ns g"))
+	errs, ok := err.(ErrorList)
+	if !ok || len(errs) != 1 {
+		t.Fatalf("got %v, want one error", err)
+	}
+	if errs[0].Line != 1 || errs[0].Col != 10 {
+		t.Errorf("error at %d:%d, want 1:10", errs[0].Line, errs[0].Col)
+	}
+}
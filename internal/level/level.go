@@ -0,0 +1,306 @@
+// Copyright 2019 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package level
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const Ext = ".field"
+
+type Dir int
+
+const (
+	DirLeft Dir = iota
+	DirRight
+)
+
+// SwitchAction is what the switches of a channel do to the linked force fields.
+type SwitchAction int
+
+const (
+	SwitchToggle SwitchAction = iota
+	SwitchOpen
+	SwitchClose
+)
+
+var switchActionNames = map[SwitchAction]string{
+	SwitchToggle: "toggle",
+	SwitchOpen:   "open",
+	SwitchClose:  "close",
+}
+
+// Channel is the behavior of the switches of a channel.
+// Switches of a channel without a 'channel' header toggle the linked force fields and latch, and the force fields start closed.
+type Channel struct {
+	// Letter is the lower case letter of the channel in ChannelLetters.
+	Letter rune
+
+	Action SwitchAction
+
+	// Latching indicates whether a switch stays pressed once the gopher steps on it.
+	// Otherwise the switch is momentary and undoes the action when the gopher leaves it.
+	Latching bool
+
+	// Open indicates whether the linked force fields are open at the start.
+	Open bool
+}
+
+// defaultOpen reports whether the linked force fields of a channel with the action are open at the start
+// unless the header says otherwise. The force fields of a 'close' channel start open so that the switches
+// can close them.
+func defaultOpen(action SwitchAction) bool {
+	return action == SwitchClose
+}
+
+// parseChannel parses a 'channel' header value like "h open momentary" or "h toggle latching open".
+// The optional last field is the initial state of the linked force fields, "open" or "closed".
+func parseChannel(value string) (Channel, bool) {
+	fs := strings.Fields(value)
+	if len(fs) != 3 && len(fs) != 4 {
+		return Channel{}, false
+	}
+	if len(fs[0]) != 1 || !strings.Contains(ChannelLetters, fs[0]) {
+		return Channel{}, false
+	}
+	c := Channel{
+		Letter: rune(fs[0][0]),
+	}
+	found := false
+	for a, n := range switchActionNames {
+		if n == fs[1] {
+			c.Action = a
+			found = true
+		}
+	}
+	if !found {
+		return Channel{}, false
+	}
+	switch fs[2] {
+	case "latching":
+		c.Latching = true
+	case "momentary":
+		c.Latching = false
+	default:
+		return Channel{}, false
+	}
+	c.Open = defaultOpen(c.Action)
+	if len(fs) == 4 {
+		switch fs[3] {
+		case "open":
+			c.Open = true
+		case "closed":
+			c.Open = false
+		default:
+			return Channel{}, false
+		}
+	}
+	return c, true
+}
+
+func (c Channel) String() string {
+	mode := "momentary"
+	if c.Latching {
+		mode = "latching"
+	}
+	s := fmt.Sprintf("%c %s %s", c.Letter, switchActionNames[c.Action], mode)
+	if c.Open != defaultOpen(c.Action) {
+		if c.Open {
+			s += " open"
+		} else {
+			s += " closed"
+		}
+	}
+	return s
+}
+
+type Level struct {
+	Name     string
+	Author   string
+	Par      int
+	StartDir Dir
+
+	// Channels is the behaviors of the channels declared in the header.
+	Channels []Channel
+
+	// Field is the ASCII grid of the field, rows separated by '\n'.
+	Field string
+
+	// FileName is the name of the file the level is loaded from.
+	FileName string
+}
+
+// Parse parses a field file.
+//
+// A field file consists of a header of 'key: value' lines, a blank line and the ASCII grid.
+// name is the file name.
+// If the file is invalid, Parse returns an ErrorList.
+func Parse(name string, data []byte) (*Level, error) {
+	lines := strings.Split(strings.Replace(string(data), "\r\n", "\n", -1), "\n")
+
+	var errs ErrorList
+	l := &Level{
+		FileName: name,
+	}
+	channels := map[rune]headerPos{}
+	i := 0
+	for ; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" {
+			i++
+			break
+		}
+		kv := strings.SplitN(line, ":", 2)
+		if len(kv) != 2 {
+			errs.add(name, i+1, 0, "header line must be 'key: value'")
+			continue
+		}
+		key := strings.TrimSpace(kv[0])
+		value := strings.TrimSpace(kv[1])
+		switch key {
+		case "name":
+			l.Name = value
+		case "author":
+			l.Author = value
+		case "par":
+			par, err := strconv.Atoi(value)
+			if err != nil || par < 0 {
+				errs.add(name, i+1, 0, "invalid par %q", value)
+				continue
+			}
+			l.Par = par
+		case "dir":
+			switch value {
+			case "left":
+				l.StartDir = DirLeft
+			case "right":
+				l.StartDir = DirRight
+			default:
+				errs.add(name, i+1, 0, "invalid dir %q", value)
+			}
+		case "channel":
+			c, ok := parseChannel(value)
+			if !ok {
+				errs.add(name, i+1, 0, "invalid channel %q", value)
+				continue
+			}
+			if _, ok := l.Channel(c.Letter); ok {
+				errs.add(name, i+1, 0, "duplicated channel %c", c.Letter)
+				continue
+			}
+			l.Channels = append(l.Channels, c)
+			v := strings.Index(lines[i], ":") + 1
+			channels[c.Letter] = headerPos{
+				line: i + 1,
+				col:  v + strings.IndexRune(lines[i][v:], c.Letter) + 1,
+			}
+		default:
+			errs.add(name, i+1, 0, "unknown header key %q", key)
+		}
+	}
+
+	// The grid follows the blank line after the header. Trim the empty lines at the end of the file.
+	// Rows of only spaces are a part of the grid.
+	lines = lines[i:]
+	firstLine := i + 1
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	var rows [][]rune
+	for _, line := range lines {
+		rows = append(rows, []rune(line))
+	}
+	errs = append(errs, validateGrid(name, rows, firstLine, channels)...)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	l.Field = strings.Join(lines, "\n")
+	return l, nil
+}
+
+// Format encodes the level in the field file format.
+func Format(l *Level) []byte {
+	var buf bytes.Buffer
+	if l.Name != "" {
+		fmt.Fprintf(&buf, "name: %s\n", l.Name)
+	}
+	if l.Author != "" {
+		fmt.Fprintf(&buf, "author: %s\n", l.Author)
+	}
+	if l.Par > 0 {
+		fmt.Fprintf(&buf, "par: %d\n", l.Par)
+	}
+	switch l.StartDir {
+	case DirLeft:
+		buf.WriteString("dir: left\n")
+	case DirRight:
+		buf.WriteString("dir: right\n")
+	default:
+		panic("not reached")
+	}
+	for _, c := range l.Channels {
+		fmt.Fprintf(&buf, "channel: %s\n", c)
+	}
+	buf.WriteString("\n")
+	buf.WriteString(l.Field)
+	buf.WriteString("\n")
+	return buf.Bytes()
+}
+
+// Channel returns the channel of the letter declared in the header.
+func (l *Level) Channel(letter rune) (Channel, bool) {
+	for _, c := range l.Channels {
+		if c.Letter == letter {
+			return c, true
+		}
+	}
+	return Channel{}, false
+}
+
+func Load(path string) (*Level, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(filepath.Base(path), data)
+}
+
+// LoadDir loads all the field files in dir, sorted by their file names.
+func LoadDir(dir string) ([]*Level, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var ls []*Level
+	for _, info := range infos {
+		if info.IsDir() || filepath.Ext(info.Name()) != Ext {
+			continue
+		}
+		l, err := Load(filepath.Join(dir, info.Name()))
+		if err != nil {
+			return nil, err
+		}
+		ls = append(ls, l)
+	}
+	return ls, nil
+}
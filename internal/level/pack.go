@@ -0,0 +1,221 @@
+// Copyright 2019 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package level
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+const ManifestName = "manifest.json"
+
+type Pack struct {
+	Name   string
+	Worlds []*World
+}
+
+type World struct {
+	Name   string
+	Levels []*Level
+
+	// Sequential indicates whether the fields in the world are unlocked one by one.
+	Sequential bool
+
+	Unlock Unlock
+}
+
+// Unlock is the condition to unlock a world.
+type Unlock struct {
+	// Worlds is the names of the worlds that must be fully cleared.
+	Worlds []string `json:"worlds"`
+
+	// Clears is the number of fields in the pack that must be cleared.
+	Clears int `json:"clears"`
+}
+
+type manifest struct {
+	Name   string `json:"name"`
+	Worlds []struct {
+		Name       string   `json:"name"`
+		Fields     []string `json:"fields"`
+		Sequential bool     `json:"sequential"`
+		Unlock     Unlock   `json:"unlock"`
+	} `json:"worlds"`
+}
+
+// ParsePack parses a manifest and the field files it lists.
+// open returns the content of a field file by its name.
+func ParsePack(manifestData []byte, open func(name string) ([]byte, error)) (*Pack, error) {
+	var m manifest
+	if err := json.Unmarshal(manifestData, &m); err != nil {
+		return nil, fmt.Errorf("level: %s: %v", ManifestName, err)
+	}
+
+	p := &Pack{
+		Name: m.Name,
+	}
+	names := map[string]bool{}
+	for _, mw := range m.Worlds {
+		if names[mw.Name] {
+			return nil, fmt.Errorf("level: %s: duplicated world %q", ManifestName, mw.Name)
+		}
+		for _, n := range mw.Unlock.Worlds {
+			if !names[n] {
+				return nil, fmt.Errorf("level: %s: world %q must be unlocked by a preceding world but %q is not", ManifestName, mw.Name, n)
+			}
+		}
+		names[mw.Name] = true
+
+		if len(mw.Fields) == 0 {
+			return nil, fmt.Errorf("level: %s: world %q has no fields", ManifestName, mw.Name)
+		}
+		w := &World{
+			Name:       mw.Name,
+			Sequential: mw.Sequential,
+			Unlock:     mw.Unlock,
+		}
+		for _, f := range mw.Fields {
+			data, err := open(f)
+			if err != nil {
+				return nil, err
+			}
+			l, err := Parse(f, data)
+			if err != nil {
+				return nil, err
+			}
+			w.Levels = append(w.Levels, l)
+		}
+		p.Worlds = append(p.Worlds, w)
+	}
+	return p, nil
+}
+
+// LoadPack loads a pack from dir.
+//
+// If dir has no manifest, all the field files in dir are put into one world.
+func LoadPack(dir string) (*Pack, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, ManifestName))
+	if os.IsNotExist(err) {
+		ls, err := LoadDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		if len(ls) == 0 {
+			return nil, fmt.Errorf("level: no field files in %s", dir)
+		}
+		return &Pack{
+			Worlds: []*World{
+				{
+					Name:   filepath.Base(dir),
+					Levels: ls,
+				},
+			},
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ParsePack(data, func(name string) ([]byte, error) {
+		return ioutil.ReadFile(filepath.Join(dir, name))
+	})
+}
+
+// Len returns the number of the fields in the pack.
+func (p *Pack) Len() int {
+	n := 0
+	for _, w := range p.Worlds {
+		n += len(w.Levels)
+	}
+	return n
+}
+
+// Level returns the field of the given ID.
+// IDs start with 1 and are numbered in the order of the manifest.
+func (p *Pack) Level(id int) (*Level, bool) {
+	w, i := p.find(id)
+	if w == nil {
+		return nil, false
+	}
+	return w.Levels[i], true
+}
+
+// ID returns the ID of the field loaded from the given file name.
+func (p *Pack) ID(fileName string) (int, bool) {
+	id := 1
+	for _, w := range p.Worlds {
+		for _, l := range w.Levels {
+			if l.FileName == fileName {
+				return id, true
+			}
+			id++
+		}
+	}
+	return 0, false
+}
+
+func (p *Pack) find(id int) (*World, int) {
+	if id < 1 {
+		return nil, 0
+	}
+	i := id - 1
+	for _, w := range p.Worlds {
+		if i < len(w.Levels) {
+			return w, i
+		}
+		i -= len(w.Levels)
+	}
+	return nil, 0
+}
+
+// IsUnlocked reports whether the field of the given ID is playable.
+// cleared reports whether the field of the given ID is cleared.
+func (p *Pack) IsUnlocked(id int, cleared func(id int) bool) bool {
+	w, i := p.find(id)
+	if w == nil {
+		return false
+	}
+
+	clears := 0
+	worldCleared := map[string]bool{}
+	first := 1
+	for _, w := range p.Worlds {
+		all := true
+		for j := range w.Levels {
+			if cleared(first + j) {
+				clears++
+			} else {
+				all = false
+			}
+		}
+		worldCleared[w.Name] = all
+		first += len(w.Levels)
+	}
+
+	if clears < w.Unlock.Clears {
+		return false
+	}
+	for _, n := range w.Unlock.Worlds {
+		if !worldCleared[n] {
+			return false
+		}
+	}
+	if w.Sequential && i > 0 && !cleared(id-1) {
+		return false
+	}
+	return true
+}
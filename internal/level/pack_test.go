@@ -0,0 +1,176 @@
+// Copyright 2019 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package level
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// openField returns a valid field file named name for any name that ends with ".field".
+func openField(name string) ([]byte, error) {
+	if !strings.HasSuffix(name, Ext) {
+		return nil, fmt.Errorf("%s not found", name)
+	}
+	return []byte("name: " + name + "\n\ns g"), nil
+}
+
+func TestParsePack(t *testing.T) {
+	cases := []struct {
+		name     string
+		manifest string
+		worlds   []string
+		levels   []string
+		err      string
+	}{
+		{
+			name: "valid",
+			manifest: `{"name": "Pack", "worlds": [
+				{"name": "A", "fields": ["01.field", "02.field"]},
+				{"name": "B", "fields": ["03.field"], "unlock": {"worlds": ["A"]}}
+			]}`,
+			worlds: []string{"A", "B"},
+			levels: []string{"01.field", "02.field", "03.field"},
+		},
+		{
+			name:     "broken JSON",
+			manifest: `{"worlds": [`,
+			err:      "level: manifest.json:",
+		},
+		{
+			name: "duplicated world",
+			manifest: `{"worlds": [
+				{"name": "A", "fields": ["01.field"]},
+				{"name": "A", "fields": ["02.field"]}
+			]}`,
+			err: `duplicated world "A"`,
+		},
+		{
+			name: "unlocked by a following world",
+			manifest: `{"worlds": [
+				{"name": "A", "fields": ["01.field"], "unlock": {"worlds": ["B"]}},
+				{"name": "B", "fields": ["02.field"]}
+			]}`,
+			err: `world "A" must be unlocked by a preceding world but "B" is not`,
+		},
+		{
+			name:     "world without fields",
+			manifest: `{"worlds": [{"name": "A", "fields": []}]}`,
+			err:      `world "A" has no fields`,
+		},
+		{
+			name:     "missing field file",
+			manifest: `{"worlds": [{"name": "A", "fields": ["01.txt"]}]}`,
+			err:      "01.txt not found",
+		},
+	}
+	for _, c := range cases {
+		p, err := ParsePack([]byte(c.manifest), openField)
+		if c.err != "" {
+			if err == nil || !strings.Contains(err.Error(), c.err) {
+				t.Errorf("%s: got error %v, want it to contain %q", c.name, err, c.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", c.name, err)
+			continue
+		}
+		var worlds []string
+		for _, w := range p.Worlds {
+			worlds = append(worlds, w.Name)
+		}
+		if got, want := strings.Join(worlds, ","), strings.Join(c.worlds, ","); got != want {
+			t.Errorf("%s: worlds: got %s, want %s", c.name, got, want)
+		}
+		if got, want := p.Len(), len(c.levels); got != want {
+			t.Errorf("%s: Len(): got %d, want %d", c.name, got, want)
+		}
+		for i, name := range c.levels {
+			l, ok := p.Level(i + 1)
+			if !ok || l.FileName != name {
+				t.Errorf("%s: Level(%d): got %v, want %s", c.name, i+1, l, name)
+			}
+			if id, ok := p.ID(name); !ok || id != i+1 {
+				t.Errorf("%s: ID(%q): got %d, %t, want %d, true", c.name, name, id, ok, i+1)
+			}
+		}
+		if _, ok := p.ID("unknown.field"); ok {
+			t.Errorf("%s: ID(%q): got true, want false", c.name, "unknown.field")
+		}
+	}
+}
+
+func TestIsUnlocked(t *testing.T) {
+	const manifest = `{"worlds": [
+		{"name": "A", "fields": ["01.field", "02.field"]},
+		{"name": "B", "fields": ["03.field", "04.field"], "sequential": true, "unlock": {"worlds": ["A"]}},
+		{"name": "C", "fields": ["05.field"], "unlock": {"clears": 3}}
+	]}`
+	p, err := ParsePack([]byte(manifest), openField)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name     string
+		cleared  []int
+		unlocked []int
+	}{
+		{
+			name:     "nothing cleared",
+			unlocked: []int{1, 2},
+		},
+		{
+			name:     "world A partially cleared",
+			cleared:  []int{1},
+			unlocked: []int{1, 2},
+		},
+		{
+			name:     "world A cleared",
+			cleared:  []int{1, 2},
+			unlocked: []int{1, 2, 3},
+		},
+		{
+			name:     "sequential world",
+			cleared:  []int{1, 2, 3},
+			unlocked: []int{1, 2, 3, 4, 5},
+		},
+		{
+			name:     "clears from any world",
+			cleared:  []int{1, 3, 4},
+			unlocked: []int{1, 2, 5},
+		},
+	}
+	for _, c := range cases {
+		cleared := map[int]bool{}
+		for _, id := range c.cleared {
+			cleared[id] = true
+		}
+		unlocked := map[int]bool{}
+		for _, id := range c.unlocked {
+			unlocked[id] = true
+		}
+		for id := 0; id <= p.Len()+1; id++ {
+			got := p.IsUnlocked(id, func(id int) bool {
+				return cleared[id]
+			})
+			if want := unlocked[id]; got != want {
+				t.Errorf("%s: IsUnlocked(%d): got %t, want %t", c.name, id, got, want)
+			}
+		}
+	}
+}
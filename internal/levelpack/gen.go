@@ -0,0 +1,77 @@
+// Copyright 2019 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build ignore
+// +build ignore
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/hajimehoshi/gopherwalk/internal/level"
+)
+
+const fieldsDir = "../../fields"
+
+func quote(str string) string {
+	if strings.Contains(str, "`") {
+		return strconv.Quote(str)
+	}
+	return "`" + str + "`"
+}
+
+func run() error {
+	infos, err := ioutil.ReadDir(fieldsDir)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by gen.go. DO NOT EDIT.\n\n")
+	buf.WriteString("package levelpack\n\n")
+	buf.WriteString("var files = map[string]string{\n")
+	for _, info := range infos {
+		if info.IsDir() {
+			continue
+		}
+		if filepath.Ext(info.Name()) != level.Ext && info.Name() != level.ManifestName {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(fieldsDir, info.Name()))
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&buf, "%q: %s,\n", info.Name(), quote(string(data)))
+	}
+	buf.WriteString("}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile("data.go", src, 0644)
+}
+
+func main() {
+	if err := run(); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright 2019 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:generate go run gen.go
+
+// Package levelpack provides the level pack in the fields directory, embedded into the binary.
+package levelpack
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/gopherwalk/internal/level"
+)
+
+func Load() (*level.Pack, error) {
+	m, ok := files[level.ManifestName]
+	if !ok {
+		return nil, fmt.Errorf("levelpack: %s is not embedded", level.ManifestName)
+	}
+	return level.ParsePack([]byte(m), func(name string) ([]byte, error) {
+		f, ok := files[name]
+		if !ok {
+			return nil, fmt.Errorf("levelpack: %s is not embedded", name)
+		}
+		return []byte(f), nil
+	})
+}
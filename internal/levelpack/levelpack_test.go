@@ -0,0 +1,46 @@
+// Copyright 2019 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package levelpack
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/gopherwalk/internal/sim"
+)
+
+// TestSolve solves all the embedded fields with the same limits as gopherwalk-solve.
+func TestSolve(t *testing.T) {
+	if testing.Short() {
+		t.Skip("solving the fields takes time")
+	}
+	pack, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for id := 1; id <= pack.Len(); id++ {
+		lv, ok := pack.Level(id)
+		if !ok {
+			t.Fatalf("Level(%d): not found", id)
+		}
+		s, err := sim.Solve(lv, 20000, 1000000)
+		if err != nil {
+			t.Errorf("%d %s: %v", id, lv.Name, err)
+			continue
+		}
+		if lv.Par > 0 && len(s.Taps) != lv.Par {
+			t.Errorf("%d %s: got %d taps, want the par %d", id, lv.Name, len(s.Taps), lv.Par)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright 2019 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resultscene
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+
+	"github.com/hajimehoshi/bitmapfont"
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/ebitenutil"
+	"github.com/hajimehoshi/ebiten/text"
+
+	"github.com/hajimehoshi/gopherwalk/internal/level"
+	"github.com/hajimehoshi/gopherwalk/internal/scene"
+	"github.com/hajimehoshi/gopherwalk/internal/ui"
+)
+
+const (
+	lineHeight = 16
+
+	// ticksPerSecond is ebiten's default TPS.
+	ticksPerSecond = 60
+)
+
+// ResultScene is shown over the cleared game.
+type ResultScene struct {
+	result  *scene.Result
+	level   *level.Level
+	buttons []*ui.Button
+	focus   ui.Focus
+	next    func(context scene.Context)
+}
+
+// New creates a result scene. last indicates whether the field is the last one in the pack.
+// nextUnlocked indicates whether the next field is unlocked. If not, Continue goes to the field selector.
+func New(result *scene.Result, lv *level.Level, last bool, nextUnlocked bool) *ResultScene {
+	s := &ResultScene{
+		result: result,
+		level:  lv,
+	}
+
+	next := func(context scene.Context) {
+		context.GoToGameScene(result.FieldID + 1)
+	}
+	switch {
+	case last:
+		next = func(context scene.Context) {
+			context.GoToEndingScene()
+		}
+	case !nextUnlocked:
+		next = func(context scene.Context) {
+			context.GoToFieldSelectorScene()
+		}
+	}
+
+	const (
+		w = 64
+		h = 16
+		y = 200
+	)
+	for i, b := range []struct {
+		text string
+		f    func(context scene.Context)
+	}{
+		{"Retry", func(context scene.Context) {
+			context.GoToGameScene(result.FieldID)
+		}},
+		{"Select", func(context scene.Context) {
+			context.GoToFieldSelectorScene()
+		}},
+		{"Continue", next},
+	} {
+		b := b
+		x := 24 + i*(w+8)
+		btn := ui.NewButton(image.Rect(x, y, x+w, y+h), b.text)
+		btn.SetOnTap(func() {
+			s.next = b.f
+		})
+		s.buttons = append(s.buttons, btn)
+	}
+	return s
+}
+
+// Stars returns the rating from 1 to 3 by comparing taps with par.
+// Stars returns 0 when par is not set.
+func Stars(taps, par int) int {
+	switch {
+	case par == 0:
+		return 0
+	case taps <= par:
+		return 3
+	case taps <= par*2:
+		return 2
+	default:
+		return 1
+	}
+}
+
+func (s *ResultScene) Update(context scene.Context) error {
+	for _, b := range s.buttons {
+		b.Update(context.Input())
+	}
+	s.focus.Update(context.Input(), s.buttons)
+	if s.next != nil {
+		s.next(context)
+		s.next = nil
+	}
+	return nil
+}
+
+func (s *ResultScene) Draw(screen *ebiten.Image) {
+	w, h := screen.Size()
+	ebitenutil.DrawRect(screen, 0, 0, float64(w), float64(h), color.NRGBA{0xff, 0xff, 0xff, 0xe0})
+
+	r := s.result
+	lines := []string{
+		fmt.Sprintf("Field %d Clear!", r.FieldID),
+		s.level.Name,
+		"",
+		fmt.Sprintf("Time:    %d ticks (%.2f s)", r.Ticks, float64(r.Ticks)/ticksPerSecond),
+		fmt.Sprintf("Toggles: %d", r.Toggles),
+		fmt.Sprintf("Turns:   %d", r.Turns),
+	}
+	if s.level.Par > 0 {
+		n := Stars(r.Taps, s.level.Par)
+		lines = append(lines,
+			fmt.Sprintf("Taps:    %d (par %d)", r.Taps, s.level.Par),
+			"",
+			strings.Repeat("★", n)+strings.Repeat("☆", 3-n))
+	} else {
+		lines = append(lines, fmt.Sprintf("Taps:    %d", r.Taps))
+	}
+	for i, l := range lines {
+		text.Draw(screen, l, bitmapfont.Gothic12r, 24, 24+i*lineHeight+12, color.Black)
+	}
+
+	for _, b := range s.buttons {
+		b.Draw(screen)
+	}
+}
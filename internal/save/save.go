@@ -0,0 +1,217 @@
+// Copyright 2019 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package save
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// Version is the current version of the save data format.
+const Version = 1
+
+type Data struct {
+	Version int `json:"version"`
+
+	// Fields is the records of fields keyed by their file names.
+	// File names are used instead of IDs so that records survive reordering a pack.
+	Fields map[string]*Record `json:"fields"`
+
+	Settings Settings `json:"settings"`
+}
+
+type Record struct {
+	Cleared    bool `json:"cleared"`
+	BestTicks  int  `json:"best_ticks"`
+	FewestTaps int  `json:"fewest_taps"`
+}
+
+type Settings struct {
+	// Turbo indicates whether the game starts in turbo mode.
+	Turbo bool `json:"turbo"`
+
+	// Bindings is the keys and gamepad buttons of actions keyed by the action names.
+	// Actions not in Bindings use the default ones.
+	Bindings map[string]*Binding `json:"bindings,omitempty"`
+}
+
+// Binding is the keys and gamepad buttons bound to an action.
+type Binding struct {
+	// Keys is the names of the keys like "Enter" or "A".
+	Keys           []string `json:"keys"`
+	GamepadButtons []int    `json:"gamepad_buttons"`
+}
+
+func New() *Data {
+	return &Data{
+		Version: Version,
+		Fields:  map[string]*Record{},
+	}
+}
+
+// migrations[i] migrates save data of version i+1 to version i+2.
+// A migration is added here when Version is bumped.
+var migrations = []func(raw map[string]interface{}) error{}
+
+// Parse parses save data, migrating it to the current version.
+func Parse(data []byte) (*Data, error) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("save: %v", err)
+	}
+
+	n, ok := raw["version"].(float64)
+	if !ok {
+		return nil, errors.New("save: no version")
+	}
+	v := int(n)
+	if v < 1 {
+		return nil, fmt.Errorf("save: invalid version %d", v)
+	}
+	if v > Version {
+		return nil, fmt.Errorf("save: version %d is newer than the supported version %d", v, Version)
+	}
+	for ; v < Version; v++ {
+		if err := migrations[v-1](raw); err != nil {
+			return nil, fmt.Errorf("save: migrating from version %d: %v", v, err)
+		}
+	}
+	raw["version"] = Version
+
+	migrated, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	d := New()
+	if err := json.Unmarshal(migrated, d); err != nil {
+		return nil, fmt.Errorf("save: %v", err)
+	}
+	if d.Fields == nil {
+		d.Fields = map[string]*Record{}
+	}
+	return d, nil
+}
+
+// Save writes the save data to path atomically.
+// The data is written to a temporary file in the same directory and then renamed,
+// so that the existing data is not broken even if the game crashes while saving.
+func (d *Data) Save(path string) error {
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(dir, filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// IsCleared reports whether the field of the given file name is cleared.
+func (d *Data) IsCleared(fileName string) bool {
+	r, ok := d.Fields[fileName]
+	return ok && r.Cleared
+}
+
+// Clear records a clear of the field and reports whether the records are updated.
+func (d *Data) Clear(fileName string, ticks, taps int) bool {
+	r, ok := d.Fields[fileName]
+	if !ok || !r.Cleared {
+		d.Fields[fileName] = &Record{
+			Cleared:    true,
+			BestTicks:  ticks,
+			FewestTaps: taps,
+		}
+		return true
+	}
+
+	updated := false
+	if ticks < r.BestTicks {
+		r.BestTicks = ticks
+		updated = true
+	}
+	if taps < r.FewestTaps {
+		r.FewestTaps = taps
+		updated = true
+	}
+	return updated
+}
+
+// DefaultPath returns the path of the save file in the user config directory.
+func DefaultPath() (string, error) {
+	dir, err := userConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gopherwalk", "save.json"), nil
+}
+
+// userConfigDir is the same as os.UserConfigDir, which is not available in Go 1.12.
+func userConfigDir() (string, error) {
+	switch runtime.GOOS {
+	case "windows":
+		if dir := os.Getenv("AppData"); dir != "" {
+			return dir, nil
+		}
+		return "", errors.New("save: %AppData% is not defined")
+	case "darwin":
+		if dir := os.Getenv("HOME"); dir != "" {
+			return filepath.Join(dir, "Library", "Application Support"), nil
+		}
+		return "", errors.New("save: $HOME is not defined")
+	case "plan9":
+		if dir := os.Getenv("home"); dir != "" {
+			return filepath.Join(dir, "lib"), nil
+		}
+		return "", errors.New("save: $home is not defined")
+	default:
+		if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+			return dir, nil
+		}
+		if dir := os.Getenv("HOME"); dir != "" {
+			return filepath.Join(dir, ".config"), nil
+		}
+		return "", errors.New("save: neither $XDG_CONFIG_HOME nor $HOME are defined")
+	}
+}
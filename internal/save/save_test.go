@@ -0,0 +1,97 @@
+// Copyright 2019 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package save
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		name string
+		data string
+		want *Data
+		err  string
+	}{
+		{
+			name: "version 1",
+			data: `{"version": 1, "fields": {}, "settings": {"turbo": true,
+				"bindings": {"turbo": {"keys": ["Y"], "gamepad_buttons": [5]}}}}`,
+			want: &Data{
+				Version: Version,
+				Fields:  map[string]*Record{},
+				Settings: Settings{
+					Turbo: true,
+					Bindings: map[string]*Binding{
+						"turbo": {Keys: []string{"Y"}, GamepadButtons: []int{5}},
+					},
+				},
+			},
+		},
+		{
+			name: "version 1 without settings",
+			data: `{"version": 1, "fields": {"01.field": {"cleared": true, "best_ticks": 300, "fewest_taps": 2}}}`,
+			want: &Data{
+				Version: Version,
+				Fields: map[string]*Record{
+					"01.field": {Cleared: true, BestTicks: 300, FewestTaps: 2},
+				},
+			},
+		},
+		{
+			name: "version 1 without fields",
+			data: `{"version": 1}`,
+			want: New(),
+		},
+		{
+			name: "no version",
+			data: `{"fields": {}}`,
+			err:  "save: no version",
+		},
+		{
+			name: "invalid version",
+			data: `{"version": 0}`,
+			err:  "save: invalid version 0",
+		},
+		{
+			name: "newer version",
+			data: `{"version": 2}`,
+			err:  "version 2 is newer than the supported version 1",
+		},
+		{
+			name: "broken JSON",
+			data: `{"version": 1`,
+			err:  "save: ",
+		},
+	}
+	for _, c := range cases {
+		got, err := Parse([]byte(c.data))
+		if c.err != "" {
+			if err == nil || !strings.Contains(err.Error(), c.err) {
+				t.Errorf("%s: got error %v, want it to contain %q", c.name, err, c.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", c.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: got %+v, want %+v", c.name, got, c.want)
+		}
+	}
+}
@@ -16,19 +16,127 @@ package scene
 
 import (
 	"github.com/hajimehoshi/ebiten"
+
+	"github.com/hajimehoshi/gopherwalk/internal/save"
 )
 
+// Context switches scenes. The scenes are a stack: the top scene is updated, and all the scenes are drawn from the bottom.
+// GoToXXX functions replace the whole stack with a transition effect.
+// The switch happens at the next frame.
 type Context interface {
 	GoToTitleScene()
 	GoToFieldSelectorScene()
 	GoToGameScene(fieldID int)
+	GoToEndingScene()
+
+	// GoToResultScene shows the result over the current scene.
+	GoToResultScene(result *Result)
+
+	// GoToEditorScene goes to the editor of the field. fieldID 0 means a new field.
+	GoToEditorScene(fieldID int)
+
+	// PushScene suspends the current scene and shows scene over it, e.g. a pause menu.
+	// PopScene removes the top scene and resumes the scene under it.
+	PushScene(scene Scene)
+	PopScene()
 
 	Input() Input
+
+	// Settings returns the user settings. SaveSettings saves the changes to them.
+	Settings() *save.Settings
+	SaveSettings() error
+}
+
+// Result is the result of a cleared field.
+type Result struct {
+	FieldID int
+	Ticks   int
+	Taps    int
+	Toggles int
+	Turns   int
+
+	// Replayed indicates whether the field is cleared by playing back a replay instead of the user's input.
+	Replayed bool
+}
+
+// Action is an input from keyboards or gamepads that doesn't depend on the device.
+type Action int
+
+const (
+	ActionUp Action = iota
+	ActionDown
+	ActionLeft
+	ActionRight
+	ActionConfirm
+	ActionCancel
+	ActionPause
+	ActionStep
+	ActionSlower
+	ActionFaster
+	ActionTurbo
+	ActionRestart
+	ActionRewind
+	ActionDebug
+)
+
+var actionNames = map[Action]string{
+	ActionUp:      "up",
+	ActionDown:    "down",
+	ActionLeft:    "left",
+	ActionRight:   "right",
+	ActionConfirm: "confirm",
+	ActionCancel:  "cancel",
+	ActionPause:   "pause",
+	ActionStep:    "step",
+	ActionSlower:  "slower",
+	ActionFaster:  "faster",
+	ActionTurbo:   "turbo",
+	ActionRestart: "restart",
+	ActionRewind:  "rewind",
+	ActionDebug:   "debug",
+}
+
+// String returns the name of the action used in save data.
+func (a Action) String() string {
+	return actionNames[a]
 }
 
+// Touch is a touch on the screen or a click of the left mouse button.
+type Touch struct {
+	ID int
+	X  int
+	Y  int
+}
+
+// MouseTouchID is the ID of Touch by the mouse.
+const MouseTouchID = -1
+
 type Input interface {
 	CursorPosition() (x, y int)
 	IsJustTapped() bool
+
+	// JustStartedTouches returns the touches started at the current tick.
+	// Unlike IsJustTapped and CursorPosition, JustStartedTouches reports all the simultaneous touches.
+	JustStartedTouches() []Touch
+
+	IsPressed() bool
+	IsKeyJustPressed(key ebiten.Key) bool
+	IsKeyPressed(key ebiten.Key) bool
+	IsGamepadButtonJustPressed(button ebiten.GamepadButton) bool
+	IsActionJustPressed(action Action) bool
+	IsActionPressed(action Action) bool
+}
+
+// Capturer is implemented by scenes that take any keys and gamepad buttons, e.g. to bind them to actions.
+// While a scene is capturing, the actions that work in any scene like the speed controls are disabled.
+type Capturer interface {
+	IsCapturing() bool
+}
+
+// Focused is implemented by scenes that have a point of interest on the screen like the gopher.
+// Transitions like the iris are centered on it.
+type Focused interface {
+	FocusPoint() (x, y int)
 }
 
 type Scene interface {
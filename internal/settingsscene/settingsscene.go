@@ -0,0 +1,143 @@
+// Copyright 2019 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package settingsscene
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/bitmapfont"
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/text"
+
+	"github.com/hajimehoshi/gopherwalk/internal/keyconfigscene"
+	"github.com/hajimehoshi/gopherwalk/internal/save"
+	"github.com/hajimehoshi/gopherwalk/internal/scene"
+	"github.com/hajimehoshi/gopherwalk/internal/ui"
+)
+
+const (
+	lineHeight = 24
+	labelX     = 24
+	valueX     = 128
+)
+
+type SettingsScene struct {
+	settings *save.Settings
+	back     func(context scene.Context)
+
+	buttons []*ui.Button
+	labels  []string
+	focus   ui.Focus
+
+	// dirty indicates whether the settings are changed.
+	dirty  bool
+	closed bool
+
+	// keyConfig indicates whether the key config is requested.
+	keyConfig bool
+}
+
+// New creates a settings scene that edits settings. back is called when the user leaves the scene.
+func New(settings *save.Settings, back func(context scene.Context)) *SettingsScene {
+	s := &SettingsScene{
+		settings: settings,
+		back:     back,
+	}
+
+	y := 32
+	add := func(rect image.Rectangle, text string, f func()) {
+		b := ui.NewButton(rect, text)
+		b.SetOnTap(func() {
+			f()
+			s.dirty = true
+		})
+		s.buttons = append(s.buttons, b)
+	}
+
+	s.labels = append(s.labels, "Turbo at start")
+	add(image.Rect(valueX, y, valueX+64, y+16), "", func() {
+		s.settings.Turbo = !s.settings.Turbo
+	})
+	y += lineHeight
+
+	s.labels = append(s.labels, "Controls")
+	controls := ui.NewButton(image.Rect(valueX, y, valueX+64, y+16), "Edit")
+	controls.SetOnTap(func() {
+		s.keyConfig = true
+	})
+	s.buttons = append(s.buttons, controls)
+	y += lineHeight * 2
+
+	b := ui.NewButton(image.Rect(labelX, y, labelX+48, y+16), "Back")
+	b.SetOnTap(func() {
+		s.closed = true
+	})
+	s.buttons = append(s.buttons, b)
+
+	s.updateTexts()
+	return s
+}
+
+func (s *SettingsScene) updateTexts() {
+	if s.settings.Turbo {
+		s.buttons[0].SetText("On")
+	} else {
+		s.buttons[0].SetText("Off")
+	}
+}
+
+func (s *SettingsScene) Update(context scene.Context) error {
+	for _, b := range s.buttons {
+		b.Update(context.Input())
+	}
+	s.focus.Update(context.Input(), s.buttons)
+	s.updateTexts()
+
+	if s.keyConfig {
+		s.keyConfig = false
+		// The key config scene saves the settings by itself.
+		context.PushScene(keyconfigscene.New(s.settings, func(context scene.Context) {
+			context.PopScene()
+		}))
+		return nil
+	}
+	if context.Input().IsActionJustPressed(scene.ActionCancel) {
+		s.closed = true
+	}
+	if !s.closed {
+		return nil
+	}
+	s.closed = false
+	if s.dirty {
+		if err := context.SaveSettings(); err != nil {
+			return err
+		}
+		s.dirty = false
+	}
+	s.back(context)
+	return nil
+}
+
+func (s *SettingsScene) Draw(screen *ebiten.Image) {
+	screen.Fill(color.White)
+	text.Draw(screen, "Settings", bitmapfont.Gothic12r, labelX, 8+12, color.Black)
+	for i, l := range s.labels {
+		text.Draw(screen, l, bitmapfont.Gothic12r, labelX, 32+i*lineHeight+12, color.Black)
+	}
+	for _, b := range s.buttons {
+		b.Draw(screen)
+	}
+}
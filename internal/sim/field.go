@@ -0,0 +1,369 @@
+// Copyright 2019 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sim
+
+import (
+	"image"
+	"strings"
+
+	"github.com/hajimehoshi/gopherwalk/internal/level"
+)
+
+type Field struct {
+	objects []Object
+
+	// areas is the areas of the objects, which never move.
+	areas []image.Rectangle
+
+	startX int
+	startY int
+
+	// width and height are the size in tiles.
+	width  int
+	height int
+}
+
+func (f *Field) StartPosition() (x, y int) {
+	return f.startX, f.startY
+}
+
+// Size returns the size of the field in tiles.
+func (f *Field) Size() (width, height int) {
+	return f.width, f.height
+}
+
+// Objects returns the objects in the field.
+func (f *Field) Objects() []Object {
+	return f.objects
+}
+
+// Conflicts reports whether rect moving to dir hits an object. Elevators are solid only from above.
+func (f *Field) Conflicts(rect image.Rectangle, dir Dir) bool {
+	return f.conflicts(rect, dir, false)
+}
+
+// conflictsExceptElevators is like Conflicts but elevators are never solid, e.g. for the gopher in an elevator.
+func (f *Field) conflictsExceptElevators(rect image.Rectangle, dir Dir) bool {
+	return f.conflicts(rect, dir, true)
+}
+
+func (f *Field) conflicts(rect image.Rectangle, dir Dir, exceptElevators bool) bool {
+	// An object far from rect never conflicts.
+	near := rect.Inset(-1)
+	for i, o := range f.objects {
+		if !f.areas[i].Overlaps(near) {
+			continue
+		}
+		if dir != DirDown || exceptElevators {
+			if _, ok := o.(*ObjectElevator); ok {
+				continue
+			}
+		}
+		if o.OverlapsWithDir(rect, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *Field) TouchesGoal(rect image.Rectangle, dir Dir) bool {
+	for _, o := range f.objects {
+		if _, ok := o.(*ObjectGoal); !ok {
+			continue
+		}
+		if o.OverlapsWithDir(rect, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// TouchedElevator returns the elevator that rect touches moving to dir, or nil.
+func (f *Field) TouchedElevator(rect image.Rectangle, dir Dir) *ObjectElevator {
+	for _, o := range f.objects {
+		e, ok := o.(*ObjectElevator)
+		if !ok {
+			continue
+		}
+		if e.OverlapsWithDir(rect, dir) {
+			return e
+		}
+	}
+	return nil
+}
+
+// ElevatorAt returns the elevator that overlaps with rect, or nil.
+func (f *Field) ElevatorAt(rect image.Rectangle) *ObjectElevator {
+	for _, o := range f.objects {
+		e, ok := o.(*ObjectElevator)
+		if !ok {
+			continue
+		}
+		if e.Overlaps(rect) {
+			return e
+		}
+	}
+	return nil
+}
+
+// hurts reports whether rect overlaps with a hazard.
+func (f *Field) hurts(rect image.Rectangle) bool {
+	for _, o := range f.objects {
+		h, ok := o.(*ObjectHazard)
+		if !ok {
+			continue
+		}
+		if h.hurtArea().Overlaps(rect) {
+			return true
+		}
+	}
+	return false
+}
+
+// teleporterAt returns the teleporter that contains rect entirely, or nil.
+func (f *Field) teleporterAt(rect image.Rectangle) *ObjectTeleporter {
+	for _, o := range f.objects {
+		t, ok := o.(*ObjectTeleporter)
+		if !ok {
+			continue
+		}
+		if rect.In(t.Area()) {
+			return t
+		}
+	}
+	return nil
+}
+
+// bounds returns the area of the field in pixels.
+func (f *Field) bounds() image.Rectangle {
+	return image.Rect(0, 0, f.width*TileWidth, f.height*TileHeight)
+}
+
+// pickKeys collects the keys that overlap with rect and returns the set of their colour bits.
+func (f *Field) pickKeys(rect image.Rectangle) int {
+	keys := 0
+	for _, o := range f.objects {
+		k, ok := o.(*ObjectKey)
+		if !ok || k.collected {
+			continue
+		}
+		if k.Area().Overlaps(rect) {
+			k.collected = true
+			keys |= k.color.bit()
+		}
+	}
+	return keys
+}
+
+// unlockDoors opens the doors whose colours are in the set of keys.
+func (f *Field) unlockDoors(keys int) {
+	for _, o := range f.objects {
+		d, ok := o.(*ObjectDoor)
+		if !ok {
+			continue
+		}
+		if keys&d.color.bit() != 0 {
+			d.open = true
+		}
+	}
+}
+
+// updateSwitches presses the switches that overlap with rect, and releases the momentary switches that don't.
+func (f *Field) updateSwitches(rect image.Rectangle) {
+	for _, o := range f.objects {
+		s, ok := o.(*ObjectSwitch)
+		if !ok {
+			continue
+		}
+		on := s.Area().Overlaps(rect)
+		switch {
+		case on && !s.pressed:
+			s.pressed = true
+			f.switchChannel(s.channel, false)
+		case !on && s.pressed && !s.channel.Latching:
+			s.pressed = false
+			f.switchChannel(s.channel, true)
+		}
+	}
+}
+
+// switchChannel applies the action of the channel to the linked force fields. If undo is true, the action is undone.
+func (f *Field) switchChannel(c level.Channel, undo bool) {
+	for _, o := range f.objects {
+		ff, ok := o.(*ObjectFF)
+		if !ok || ff.channel != c.Letter {
+			continue
+		}
+		switch c.Action {
+		case level.SwitchToggle:
+			ff.on = !ff.on
+		case level.SwitchOpen:
+			ff.on = undo
+		case level.SwitchClose:
+			ff.on = !undo
+		default:
+			panic("not reached")
+		}
+	}
+}
+
+func (f *Field) Update(input Input) {
+	for _, t := range f.objects {
+		t.Update(input)
+	}
+}
+
+// strToField converts a grid to a field. The grid must be validated by the level package.
+// channels is the channels declared in the level. The other channels toggle the force fields and latch.
+func strToField(str string, channels []level.Channel) *Field {
+	lines := strings.Split(str, "\n")
+
+	channel := func(letter rune) level.Channel {
+		for _, c := range channels {
+			if c.Letter == letter {
+				return c
+			}
+		}
+		return level.Channel{
+			Letter:   letter,
+			Action:   level.SwitchToggle,
+			Latching: true,
+		}
+	}
+
+	at := func(x, y int) byte {
+		if y < 0 || len(lines) <= y || x < 0 || len(lines[y]) <= x {
+			return 0
+		}
+		return lines[y][x]
+	}
+
+	// isWall reports whether (x, y) is covered by a wall.
+	isWall := func(x, y int) bool {
+		for j := y - 1; j <= y; j++ {
+			for i := x - 1; i <= x; i++ {
+				if j < 0 || len(lines) <= j || i < 0 || len(lines[j]) <= i {
+					continue
+				}
+				switch lines[j][i] {
+				case level.GlyphWall:
+					if i == x && j == y {
+						return true
+					}
+				case level.GlyphBigWall:
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	f := &Field{
+		height: len(lines),
+	}
+	teleporters := map[rune]*ObjectTeleporter{}
+	for j, line := range lines {
+		if len(line) > f.width {
+			f.width = len(line)
+		}
+		for i, c := range line {
+			switch c {
+			case level.GlyphBigWall:
+				f.objects = append(f.objects, &ObjectWall{big: true, x: i, y: j})
+			case level.GlyphWall:
+				n := 0
+				if isWall(i, j-1) {
+					n |= wallUp
+				}
+				if isWall(i+1, j) {
+					n |= wallRight
+				}
+				if isWall(i, j+1) {
+					n |= wallDown
+				}
+				if isWall(i-1, j) {
+					n |= wallLeft
+				}
+				f.objects = append(f.objects, &ObjectWall{big: false, x: i, y: j, neighbors: n})
+			case level.GlyphBigForceField:
+				f.objects = append(f.objects, &ObjectFF{big: true, x: i, y: j})
+			case level.GlyphForceField:
+				f.objects = append(f.objects, &ObjectFF{big: false, x: i, y: j})
+			case level.GlyphElevator, level.GlyphDownElevator, level.GlyphToggleElevator:
+				// A vertical run of the same elevator glyphs is one shaft.
+				if at(i, j-1) == byte(c) {
+					continue
+				}
+				h := 1
+				for at(i, j+h) == byte(c) {
+					h++
+				}
+				f.objects = append(f.objects, &ObjectElevator{
+					x:          i,
+					y:          j,
+					height:     h,
+					down:       c == level.GlyphDownElevator,
+					toggleable: c == level.GlyphToggleElevator,
+				})
+			case level.GlyphStart:
+				f.startX = i
+				f.startY = j
+			case level.GlyphGoal:
+				f.objects = append(f.objects, &ObjectGoal{x: i, y: j})
+			case level.GlyphRedKey:
+				f.objects = append(f.objects, &ObjectKey{x: i, y: j, color: KeyRed})
+			case level.GlyphBlueKey:
+				f.objects = append(f.objects, &ObjectKey{x: i, y: j, color: KeyBlue})
+			case level.GlyphYellowKey:
+				f.objects = append(f.objects, &ObjectKey{x: i, y: j, color: KeyYellow})
+			case level.GlyphRedDoor:
+				f.objects = append(f.objects, &ObjectDoor{x: i, y: j, color: KeyRed})
+			case level.GlyphBlueDoor:
+				f.objects = append(f.objects, &ObjectDoor{x: i, y: j, color: KeyBlue})
+			case level.GlyphYellowDoor:
+				f.objects = append(f.objects, &ObjectDoor{x: i, y: j, color: KeyYellow})
+			case level.GlyphSpikes:
+				f.objects = append(f.objects, &ObjectHazard{x: i, y: j})
+			case level.GlyphLava:
+				f.objects = append(f.objects, &ObjectHazard{x: i, y: j, lava: true})
+			case level.GlyphEmpty, level.GlyphFiller:
+			default:
+				if level.IsTeleporter(c) {
+					t := &ObjectTeleporter{x: i, y: j, digit: c}
+					if l, ok := teleporters[c]; ok {
+						t.link = l
+						l.link = t
+					}
+					teleporters[c] = t
+					f.objects = append(f.objects, t)
+					break
+				}
+				if ch, ok := level.SwitchChannel(c); ok {
+					f.objects = append(f.objects, &ObjectSwitch{x: i, y: j, channel: channel(ch)})
+					break
+				}
+				if ch, ok := level.LinkedForceFieldChannel(c); ok {
+					f.objects = append(f.objects, &ObjectFF{x: i, y: j, channel: ch, on: !channel(ch).Open})
+					break
+				}
+				panic("not reached")
+			}
+		}
+	}
+	for _, o := range f.objects {
+		f.areas = append(f.areas, o.Area())
+	}
+	return f
+}
@@ -0,0 +1,565 @@
+// Copyright 2019 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sim
+
+import (
+	"image"
+
+	"github.com/hajimehoshi/gopherwalk/internal/level"
+)
+
+type Dir int
+
+const (
+	DirLeft Dir = iota
+	DirRight
+	DirUp
+	DirDown
+)
+
+const (
+	TileWidth  = 16
+	TileHeight = 16
+)
+
+func shift(area image.Rectangle, dir Dir) image.Rectangle {
+	switch dir {
+	case DirLeft:
+		area.Min.X--
+		area.Max.X--
+	case DirRight:
+		area.Min.X++
+		area.Max.X++
+	case DirUp:
+		area.Min.Y--
+		area.Max.Y--
+	case DirDown:
+		area.Min.Y++
+		area.Max.Y++
+	default:
+		panic("not reached")
+	}
+	return area
+}
+
+func Edge(area image.Rectangle, from Dir) image.Rectangle {
+	switch from {
+	case DirLeft:
+		area.Min.X = area.Max.X - 1
+	case DirRight:
+		area.Max.X = area.Min.X + 1
+	case DirUp:
+		area.Min.Y = area.Max.Y - 1
+	case DirDown:
+		area.Max.Y = area.Min.Y + 1
+	default:
+		panic("not reached")
+	}
+	return area
+}
+
+type Object interface {
+	// Position returns the top-left tile of the object.
+	Position() (x, y int)
+
+	// Area returns the area of the object in pixels.
+	Area() image.Rectangle
+
+	OverlapsWithDir(rect image.Rectangle, dir Dir) bool
+
+	Update(input Input)
+}
+
+// stateful is implemented by objects whose state changes while playing.
+type stateful interface {
+	state() int
+	setState(state int)
+}
+
+// tappable is implemented by objects that can react to taps.
+type tappable interface {
+	// tapArea returns the area that reacts to taps, or an empty rectangle if the object doesn't react to taps.
+	tapArea() image.Rectangle
+}
+
+// isTappable reports whether o reacts to taps.
+func isTappable(o Object) bool {
+	t, ok := o.(tappable)
+	return ok && !t.tapArea().Empty()
+}
+
+// isTapped reports whether any tap is in area.
+func isTapped(input Input, area image.Rectangle) bool {
+	for _, t := range input.Taps() {
+		if t.In(area) {
+			return true
+		}
+	}
+	return false
+}
+
+// Neighbor bits of walls for auto-tiling.
+const (
+	wallUp = 1 << iota
+	wallRight
+	wallDown
+	wallLeft
+)
+
+type ObjectWall struct {
+	big bool
+	x   int
+	y   int
+
+	// neighbors is the set of the wallXXX bits of adjacent walls.
+	neighbors int
+}
+
+func (o *ObjectWall) Position() (x, y int) {
+	return o.x, o.y
+}
+
+func (o *ObjectWall) Big() bool {
+	return o.big
+}
+
+// Neighbors returns the set of the bits of adjacent walls for auto-tiling.
+func (o *ObjectWall) Neighbors() int {
+	return o.neighbors
+}
+
+func (o *ObjectWall) Area() image.Rectangle {
+	w := TileWidth
+	h := TileHeight
+	if o.big {
+		w *= 2
+		h *= 2
+	}
+	return image.Rect(o.x*TileWidth, o.y*TileHeight, o.x*TileWidth+w, o.y*TileHeight+h)
+}
+
+func (o *ObjectWall) OverlapsWithDir(rect image.Rectangle, dir Dir) bool {
+	return Edge(o.Area(), dir).Overlaps(shift(rect, dir))
+}
+
+func (o *ObjectWall) Update(input Input) {
+}
+
+type ObjectFF struct {
+	big bool
+	x   int
+	y   int
+
+	// channel is the channel letter of the switches that control the force field, or 0 if taps control it.
+	channel rune
+
+	on bool
+}
+
+func (o *ObjectFF) Position() (x, y int) {
+	return o.x, o.y
+}
+
+func (o *ObjectFF) Big() bool {
+	return o.big
+}
+
+func (o *ObjectFF) Channel() rune {
+	return o.channel
+}
+
+func (o *ObjectFF) On() bool {
+	return o.on
+}
+
+func (o *ObjectFF) Area() image.Rectangle {
+	w := TileWidth
+	h := TileHeight
+	if o.big {
+		w *= 2
+		h *= 2
+	}
+	return image.Rect(o.x*TileWidth, o.y*TileHeight, o.x*TileWidth+w, o.y*TileHeight+h)
+}
+
+func (o *ObjectFF) OverlapsWithDir(rect image.Rectangle, dir Dir) bool {
+	if !o.on {
+		return false
+	}
+	return Edge(o.Area(), dir).Overlaps(shift(rect, dir))
+}
+
+func (o *ObjectFF) tapArea() image.Rectangle {
+	if o.channel != 0 {
+		return image.Rectangle{}
+	}
+	return o.Area()
+}
+
+func (o *ObjectFF) state() int {
+	if o.on {
+		return 1
+	}
+	return 0
+}
+
+func (o *ObjectFF) setState(state int) {
+	o.on = state != 0
+}
+
+func (o *ObjectFF) Update(input Input) {
+	if o.channel != 0 {
+		return
+	}
+	if !isTapped(input, o.Area()) {
+		return
+	}
+	o.on = !o.on
+}
+
+// ObjectElevator is a vertical shaft of elevator tiles.
+// An elevator is solid only from above. The gopher rides it up, or down if down is true.
+type ObjectElevator struct {
+	x int
+	y int
+
+	// height is the number of tiles of the shaft.
+	height int
+
+	down bool
+
+	// toggleable indicates whether a tap flips the direction.
+	toggleable bool
+}
+
+func (o *ObjectElevator) Position() (x, y int) {
+	return o.x, o.y
+}
+
+func (o *ObjectElevator) Height() int {
+	return o.height
+}
+
+func (o *ObjectElevator) Down() bool {
+	return o.down
+}
+
+func (o *ObjectElevator) Toggleable() bool {
+	return o.toggleable
+}
+
+func (o *ObjectElevator) Area() image.Rectangle {
+	w := TileWidth
+	h := TileHeight * o.height
+	return image.Rect(o.x*TileWidth, o.y*TileHeight, o.x*TileWidth+w, o.y*TileHeight+h)
+}
+
+func (o *ObjectElevator) Overlaps(rect image.Rectangle) bool {
+	return o.Area().Overlaps(rect)
+}
+
+func (o *ObjectElevator) OverlapsWithDir(rect image.Rectangle, dir Dir) bool {
+	return Edge(o.Area(), dir).Overlaps(shift(rect, dir))
+}
+
+func (o *ObjectElevator) tapArea() image.Rectangle {
+	if !o.toggleable {
+		return image.Rectangle{}
+	}
+	return o.Area()
+}
+
+func (o *ObjectElevator) state() int {
+	if o.down {
+		return 1
+	}
+	return 0
+}
+
+func (o *ObjectElevator) setState(state int) {
+	o.down = state != 0
+}
+
+func (o *ObjectElevator) Update(input Input) {
+	if !o.toggleable {
+		return
+	}
+	if !isTapped(input, o.Area()) {
+		return
+	}
+	o.down = !o.down
+}
+
+type ObjectGoal struct {
+	x int
+	y int
+}
+
+func (o *ObjectGoal) Position() (x, y int) {
+	return o.x, o.y
+}
+
+func (o *ObjectGoal) Area() image.Rectangle {
+	w := TileWidth
+	h := TileHeight
+	return image.Rect(o.x*TileWidth, o.y*TileHeight, o.x*TileWidth+w, o.y*TileHeight+h)
+}
+
+func (o *ObjectGoal) OverlapsWithDir(rect image.Rectangle, dir Dir) bool {
+	return Edge(o.Area(), dir).Overlaps(shift(rect, dir))
+}
+
+func (o *ObjectGoal) Update(input Input) {
+}
+
+// KeyColor is the colour of a key and the doors it opens.
+type KeyColor int
+
+const (
+	KeyRed KeyColor = iota
+	KeyBlue
+	KeyYellow
+)
+
+var KeyColors = []KeyColor{KeyRed, KeyBlue, KeyYellow}
+
+func (c KeyColor) String() string {
+	switch c {
+	case KeyRed:
+		return "red"
+	case KeyBlue:
+		return "blue"
+	case KeyYellow:
+		return "yellow"
+	default:
+		panic("not reached")
+	}
+}
+
+// bit returns the bit of the colour in a set of keys.
+func (c KeyColor) bit() int {
+	return 1 << uint(c)
+}
+
+// ObjectKey is a key that the gopher collects by walking through it.
+type ObjectKey struct {
+	x     int
+	y     int
+	color KeyColor
+
+	collected bool
+}
+
+func (o *ObjectKey) Position() (x, y int) {
+	return o.x, o.y
+}
+
+func (o *ObjectKey) Color() KeyColor {
+	return o.color
+}
+
+func (o *ObjectKey) Collected() bool {
+	return o.collected
+}
+
+func (o *ObjectKey) Area() image.Rectangle {
+	w := TileWidth
+	h := TileHeight
+	return image.Rect(o.x*TileWidth, o.y*TileHeight, o.x*TileWidth+w, o.y*TileHeight+h)
+}
+
+// OverlapsWithDir implements Object. A key never blocks the gopher.
+func (o *ObjectKey) OverlapsWithDir(rect image.Rectangle, dir Dir) bool {
+	return false
+}
+
+func (o *ObjectKey) state() int {
+	if o.collected {
+		return 1
+	}
+	return 0
+}
+
+func (o *ObjectKey) setState(state int) {
+	o.collected = state != 0
+}
+
+func (o *ObjectKey) Update(input Input) {
+}
+
+// ObjectDoor is a door that is solid until the gopher collects a key of the same colour.
+type ObjectDoor struct {
+	x     int
+	y     int
+	color KeyColor
+
+	open bool
+}
+
+func (o *ObjectDoor) Position() (x, y int) {
+	return o.x, o.y
+}
+
+func (o *ObjectDoor) Color() KeyColor {
+	return o.color
+}
+
+func (o *ObjectDoor) Open() bool {
+	return o.open
+}
+
+func (o *ObjectDoor) Area() image.Rectangle {
+	w := TileWidth
+	h := TileHeight
+	return image.Rect(o.x*TileWidth, o.y*TileHeight, o.x*TileWidth+w, o.y*TileHeight+h)
+}
+
+func (o *ObjectDoor) OverlapsWithDir(rect image.Rectangle, dir Dir) bool {
+	if o.open {
+		return false
+	}
+	return Edge(o.Area(), dir).Overlaps(shift(rect, dir))
+}
+
+func (o *ObjectDoor) state() int {
+	if o.open {
+		return 1
+	}
+	return 0
+}
+
+func (o *ObjectDoor) setState(state int) {
+	o.open = state != 0
+}
+
+func (o *ObjectDoor) Update(input Input) {
+}
+
+// ObjectSwitch is a floor switch that the gopher presses by stepping on it.
+// The switch acts on the force fields linked to its channel when it is pressed,
+// and a momentary switch undoes the action when the gopher leaves it.
+type ObjectSwitch struct {
+	x       int
+	y       int
+	channel level.Channel
+
+	pressed bool
+}
+
+func (o *ObjectSwitch) Position() (x, y int) {
+	return o.x, o.y
+}
+
+func (o *ObjectSwitch) Channel() level.Channel {
+	return o.channel
+}
+
+func (o *ObjectSwitch) Pressed() bool {
+	return o.pressed
+}
+
+func (o *ObjectSwitch) Area() image.Rectangle {
+	w := TileWidth
+	h := TileHeight
+	return image.Rect(o.x*TileWidth, o.y*TileHeight, o.x*TileWidth+w, o.y*TileHeight+h)
+}
+
+// OverlapsWithDir implements Object. A switch never blocks the gopher.
+func (o *ObjectSwitch) OverlapsWithDir(rect image.Rectangle, dir Dir) bool {
+	return false
+}
+
+func (o *ObjectSwitch) state() int {
+	if o.pressed {
+		return 1
+	}
+	return 0
+}
+
+func (o *ObjectSwitch) setState(state int) {
+	o.pressed = state != 0
+}
+
+func (o *ObjectSwitch) Update(input Input) {
+}
+
+// ObjectHazard is spikes or lava that kills the gopher.
+type ObjectHazard struct {
+	x    int
+	y    int
+	lava bool
+}
+
+func (o *ObjectHazard) Position() (x, y int) {
+	return o.x, o.y
+}
+
+func (o *ObjectHazard) Lava() bool {
+	return o.lava
+}
+
+func (o *ObjectHazard) Area() image.Rectangle {
+	return image.Rect(o.x*TileWidth, o.y*TileHeight, (o.x+1)*TileWidth, (o.y+1)*TileHeight)
+}
+
+// hurtArea returns the area where the gopher dies.
+// Spikes are at the bottom of the tile, and they don't hurt at the very edges so that the gopher can walk by.
+func (o *ObjectHazard) hurtArea() image.Rectangle {
+	x := o.x * TileWidth
+	y := o.y * TileHeight
+	if o.lava {
+		return image.Rect(x, y+TileHeight/4, x+TileWidth, y+TileHeight)
+	}
+	return image.Rect(x+2, y+TileHeight/2, x+TileWidth-2, y+TileHeight)
+}
+
+// OverlapsWithDir implements Object. A hazard never blocks the gopher.
+func (o *ObjectHazard) OverlapsWithDir(rect image.Rectangle, dir Dir) bool {
+	return false
+}
+
+func (o *ObjectHazard) Update(input Input) {
+}
+
+// ObjectTeleporter is a teleporter pad. The gopher entering a pad reappears at the linked pad.
+type ObjectTeleporter struct {
+	x     int
+	y     int
+	digit rune
+
+	link *ObjectTeleporter
+}
+
+func (o *ObjectTeleporter) Position() (x, y int) {
+	return o.x, o.y
+}
+
+func (o *ObjectTeleporter) Digit() rune {
+	return o.digit
+}
+
+func (o *ObjectTeleporter) Area() image.Rectangle {
+	w := TileWidth
+	h := TileHeight
+	return image.Rect(o.x*TileWidth, o.y*TileHeight, o.x*TileWidth+w, o.y*TileHeight+h)
+}
+
+// OverlapsWithDir implements Object. A teleporter never blocks the gopher.
+func (o *ObjectTeleporter) OverlapsWithDir(rect image.Rectangle, dir Dir) bool {
+	return false
+}
+
+func (o *ObjectTeleporter) Update(input Input) {
+}
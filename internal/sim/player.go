@@ -0,0 +1,368 @@
+// Copyright 2019 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sim
+
+import (
+	"fmt"
+	"image"
+)
+
+const PlayerUnit = 32
+
+// maxFallHeight is the height in 1/PlayerUnit tiles of the longest drop the gopher survives.
+const maxFallHeight = 8 * PlayerUnit
+
+// teleportCooldown is the number of ticks after teleporting before the gopher can teleport again.
+// Without this, the gopher would go back and forth between the pads.
+const teleportCooldown = 2 * PlayerUnit
+
+// DyingDuration is the number of ticks of the dying animation.
+const DyingDuration = 60
+
+type Player struct {
+	x32        int
+	y32        int
+	dir        Dir
+	climbing   bool
+	descending bool
+	falling    bool
+	atGoal     bool
+
+	// keys is the set of the colour bits of the collected keys.
+	keys int
+
+	// fallFrom is the y32 where the current drop started.
+	fallFrom int
+
+	// teleporting is the number of ticks left before the gopher can teleport again.
+	teleporting int
+
+	// dead indicates whether the gopher is dead. dying is the number of ticks since the death.
+	dead  bool
+	dying int
+
+	// turns is the number of turns by taps.
+	turns int
+
+	// turning is the number of ticks left to show the turning animation.
+	turning int
+}
+
+func NewPlayer(x, y int, dir Dir) *Player {
+	return &Player{
+		x32: x * PlayerUnit,
+		y32: y * PlayerUnit,
+		dir: dir,
+	}
+}
+
+func (p *Player) AtGoal() bool {
+	return p.atGoal
+}
+
+// Dead reports whether the gopher is dead. The gopher doesn't move any more.
+func (p *Player) Dead() bool {
+	return p.dead
+}
+
+// Dying returns the number of ticks since the death, up to DyingDuration.
+func (p *Player) Dying() int {
+	return p.dying
+}
+
+func (p *Player) die() {
+	p.dead = true
+	p.dying = 0
+}
+
+type PlayerState int
+
+const (
+	PlayerStateWalking PlayerState = iota
+	PlayerStateClimbing
+	PlayerStateFalling
+	PlayerStateAtGoal
+	PlayerStateDescending
+	PlayerStateDead
+)
+
+func (s PlayerState) String() string {
+	switch s {
+	case PlayerStateWalking:
+		return "walking"
+	case PlayerStateClimbing:
+		return "climbing"
+	case PlayerStateFalling:
+		return "falling"
+	case PlayerStateAtGoal:
+		return "at goal"
+	case PlayerStateDescending:
+		return "descending"
+	case PlayerStateDead:
+		return "dead"
+	default:
+		return fmt.Sprintf("PlayerState(%d)", int(s))
+	}
+}
+
+func (p *Player) State() PlayerState {
+	switch {
+	case p.dead:
+		return PlayerStateDead
+	case p.atGoal:
+		return PlayerStateAtGoal
+	case p.falling:
+		return PlayerStateFalling
+	case p.climbing:
+		return PlayerStateClimbing
+	case p.descending:
+		return PlayerStateDescending
+	default:
+		return PlayerStateWalking
+	}
+}
+
+// Position returns the player's position in 1/PlayerUnit tiles.
+func (p *Player) Position() (x, y int) {
+	return p.x32, p.y32
+}
+
+func (p *Player) Dir() Dir {
+	return p.dir
+}
+
+// Turning reports whether the gopher has just turned and the turning animation should be shown.
+func (p *Player) Turning() bool {
+	return p.turning > 0
+}
+
+// Turns returns the number of times the player has been turned by taps.
+func (p *Player) Turns() int {
+	return p.turns
+}
+
+// HasKey reports whether the gopher has collected a key of the colour.
+func (p *Player) HasKey(c KeyColor) bool {
+	return p.keys&c.bit() != 0
+}
+
+func (p *Player) Update(input Input, f *Field) {
+	if p.dead {
+		if p.dying < DyingDuration {
+			p.dying++
+		}
+		return
+	}
+
+	if p.turning > 0 {
+		p.turning--
+	}
+
+	// A collected key opens the doors at once.
+	if k := f.pickKeys(p.ConflictionArea()); k != 0 {
+		p.keys |= k
+		f.unlockDoors(p.keys)
+	}
+	f.updateSwitches(p.ConflictionArea())
+
+	var e *ObjectElevator
+	switch {
+	case p.climbing:
+		e = f.ElevatorAt(p.ConflictionArea())
+	case p.descending:
+		// The gopher entering from the top is still right above the elevator.
+		e = f.ElevatorAt(shift(p.ConflictionArea(), DirDown))
+	default:
+		e = p.enteringElevator(f)
+	}
+
+	// An elevator can be toggled while the gopher is in it. The gopher follows the current direction.
+	// Riding an elevator breaks a drop.
+	if e != nil && !e.down {
+		p.y32--
+		p.climbing = true
+		p.descending = false
+		p.fallFrom = p.y32
+	} else if e != nil && e.down && !f.conflictsExceptElevators(p.FootArea(), DirDown) {
+		p.y32++
+		p.climbing = false
+		p.descending = true
+		p.fallFrom = p.y32
+	} else if !f.Conflicts(p.FootArea(), DirDown) {
+		if !p.falling {
+			// The gopher leaving the bottom of an elevator falls straight.
+			if !p.descending {
+				switch p.dir {
+				case DirLeft:
+					p.x32 -= 8
+				case DirRight:
+					p.x32 += 8
+				default:
+					panic("not reached")
+				}
+			}
+			p.fallFrom = p.y32
+		}
+		for i := 0; i < 3 && !f.Conflicts(p.FootArea(), DirDown); i++ {
+			p.y32++
+		}
+		p.falling = true
+		p.climbing = false
+		p.descending = false
+	} else {
+		if p.falling && p.y32-p.fallFrom > maxFallHeight {
+			p.die()
+			return
+		}
+		p.falling = false
+		p.climbing = false
+		p.descending = false
+	}
+
+	if f.hurts(p.ConflictionArea()) || !p.ConflictionArea().Overlaps(f.bounds()) {
+		p.die()
+		return
+	}
+
+	// Teleport keeping the direction. Teleporting breaks a drop.
+	if p.teleporting > 0 {
+		p.teleporting--
+	} else if t := f.teleporterAt(p.ConflictionArea()); t != nil {
+		p.x32 = t.link.x * PlayerUnit
+		p.y32 = t.link.y * PlayerUnit
+		p.falling = false
+		p.climbing = false
+		p.descending = false
+		p.fallFrom = p.y32
+		p.teleporting = teleportCooldown
+	}
+
+	if p.falling {
+		return
+	}
+
+	if f.TouchesGoal(p.ConflictionArea(), p.dir) {
+		p.atGoal = true
+		return
+	}
+
+	// Move left or right.
+	if !p.climbing && !p.descending {
+		switch p.dir {
+		case DirLeft:
+			if f.Conflicts(p.ConflictionArea(), p.dir) {
+				p.dir = DirRight
+				p.turning = turnDuration
+			} else {
+				p.x32--
+			}
+		case DirRight:
+			if f.Conflicts(p.ConflictionArea(), p.dir) {
+				p.dir = DirLeft
+				p.turning = turnDuration
+			} else {
+				p.x32++
+			}
+		default:
+			panic("not reached")
+		}
+	}
+
+	// Turn by tapping.
+	if isTapped(input, p.ClickableArea()) {
+		switch p.dir {
+		case DirLeft:
+			p.dir = DirRight
+		case DirRight:
+			p.dir = DirLeft
+		default:
+			panic("not reached")
+		}
+		p.turns++
+		p.turning = turnDuration
+	}
+}
+
+// enteringElevator returns the elevator the gopher enters, or nil.
+// The gopher enters an elevator from the side, or a down elevator from the top when the gopher is right above it.
+func (p *Player) enteringElevator(f *Field) *ObjectElevator {
+	if e := f.TouchedElevator(p.ElevatorArea(), p.dir); e != nil {
+		return e
+	}
+	e := f.ElevatorAt(shift(p.ConflictionArea(), DirDown))
+	if e != nil && e.down && p.x32 == e.x*PlayerUnit {
+		return e
+	}
+	return nil
+}
+
+func (p *Player) ConflictionArea() image.Rectangle {
+	x := p.x32 * TileWidth / PlayerUnit
+	y := p.y32 * TileHeight / PlayerUnit
+	return image.Rect(x, y, x+TileWidth, y+TileHeight)
+}
+
+func (p *Player) ElevatorArea() image.Rectangle {
+	x := 0
+	switch p.dir {
+	case DirLeft:
+		x = (p.x32*TileWidth)/PlayerUnit + TileWidth*3/4
+	case DirRight:
+		x = (p.x32*TileWidth)/PlayerUnit + TileWidth/4 - 1
+	default:
+		panic("not reached")
+	}
+	y := (p.y32 * TileHeight) / PlayerUnit
+	return image.Rect(x, y, x+1, y+TileHeight)
+}
+
+func (p *Player) ClickableArea() image.Rectangle {
+	x := p.x32*TileWidth/PlayerUnit - TileWidth/2
+	y := p.y32*TileHeight/PlayerUnit - TileHeight
+	return image.Rect(x, y, x+TileWidth*2, y+TileHeight*2)
+}
+
+func (p *Player) FootArea() image.Rectangle {
+	x := 0
+	switch p.dir {
+	case DirLeft:
+		x = (p.x32 * TileWidth) / PlayerUnit
+	case DirRight:
+		x = (p.x32*TileWidth)/PlayerUnit + TileWidth/2
+	default:
+		panic("not reached")
+	}
+	y := (p.y32*TileHeight)/PlayerUnit + TileHeight - 1
+	return image.Rect(x, y, x+TileWidth/2, y+1)
+}
+
+// turnDuration is the number of ticks to show the turning gopher.
+const turnDuration = 8
+
+// affectingArea returns the area where objects can affect the gopher's movement at the next update: the gopher,
+// the front where the gopher moves or starts falling, and the feet.
+func (p *Player) affectingArea() image.Rectangle {
+	r := p.ConflictionArea()
+	switch p.dir {
+	case DirLeft:
+		r.Min.X -= TileWidth/4 + 1
+	case DirRight:
+		r.Max.X += TileWidth/4 + 1
+	default:
+		panic("not reached")
+	}
+	r.Max.Y += TileHeight / 4
+	return r
+}
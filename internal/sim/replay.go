@@ -0,0 +1,119 @@
+// Copyright 2019 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sim
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+)
+
+const ReplayExt = ".replay"
+
+const (
+	replayMagic   = "GWRP"
+	replayVersion = 2
+)
+
+// Replay is a record of the taps in a run of a field.
+type Replay struct {
+	// FileName is the file name of the field, which doesn't change when fields are reordered.
+	FileName string
+
+	Taps []Tap
+}
+
+// MarshalBinary encodes the replay.
+//
+// The format is the magic "GWRP", the version byte, the length varint and the bytes of the file name,
+// and varints of the number of the taps and the tick delta and the position of each tap.
+func (r *Replay) MarshalBinary() ([]byte, error) {
+	b := []byte(replayMagic)
+	b = append(b, replayVersion)
+	b = appendVarint(b, len(r.FileName))
+	b = append(b, r.FileName...)
+	b = appendVarint(b, len(r.Taps))
+	prev := 0
+	for _, t := range r.Taps {
+		if t.Tick < prev {
+			return nil, errors.New("sim: taps in a replay must be sorted by ticks")
+		}
+		b = appendVarint(b, t.Tick-prev)
+		b = appendVarint(b, t.X)
+		b = appendVarint(b, t.Y)
+		prev = t.Tick
+	}
+	return b, nil
+}
+
+func (r *Replay) UnmarshalBinary(data []byte) error {
+	if !bytes.HasPrefix(data, []byte(replayMagic)) {
+		return errors.New("sim: not a replay")
+	}
+	data = data[len(replayMagic):]
+	if len(data) == 0 || data[0] != replayVersion {
+		return errors.New("sim: unsupported replay version")
+	}
+	data = data[1:]
+
+	next := func() (int, error) {
+		v, n := binary.Varint(data)
+		if n <= 0 {
+			return 0, errors.New("sim: broken replay")
+		}
+		data = data[n:]
+		return int(v), nil
+	}
+
+	l, err := next()
+	if err != nil {
+		return err
+	}
+	if l < 0 || l > len(data) {
+		return errors.New("sim: broken replay")
+	}
+	name := string(data[:l])
+	data = data[l:]
+
+	n, err := next()
+	if err != nil {
+		return err
+	}
+	var taps []Tap
+	tick := 0
+	for i := 0; i < n; i++ {
+		var vs [3]int
+		for j := range vs {
+			v, err := next()
+			if err != nil {
+				return err
+			}
+			vs[j] = v
+		}
+		tick += vs[0]
+		taps = append(taps, Tap{
+			Tick: tick,
+			X:    vs[1],
+			Y:    vs[2],
+		})
+	}
+	if len(data) > 0 {
+		return errors.New("sim: broken replay")
+	}
+
+	r.FileName = name
+	r.Taps = taps
+	return nil
+}
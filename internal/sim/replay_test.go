@@ -0,0 +1,104 @@
+// Copyright 2019 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sim
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReplayRoundTrip(t *testing.T) {
+	cases := []struct {
+		name   string
+		replay *Replay
+	}{
+		{
+			name:   "no taps",
+			replay: &Replay{FileName: "01.field"},
+		},
+		{
+			name: "taps",
+			replay: &Replay{
+				FileName: "12.field",
+				Taps: []Tap{
+					{Tick: 0, X: 8, Y: 8},
+					{Tick: 0, X: 24, Y: 8},
+					{Tick: 130, X: 200, Y: 120},
+					{Tick: 100000, X: 0, Y: 4000},
+				},
+			},
+		},
+		{
+			name: "negative positions",
+			replay: &Replay{
+				FileName: "03.field",
+				Taps: []Tap{
+					{Tick: 3, X: -1, Y: -20},
+				},
+			},
+		},
+	}
+	for _, c := range cases {
+		data, err := c.replay.MarshalBinary()
+		if err != nil {
+			t.Errorf("%s: MarshalBinary: %v", c.name, err)
+			continue
+		}
+		var got Replay
+		if err := got.UnmarshalBinary(data); err != nil {
+			t.Errorf("%s: UnmarshalBinary: %v", c.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(&got, c.replay) {
+			t.Errorf("%s: got %+v, want %+v", c.name, &got, c.replay)
+		}
+	}
+}
+
+func TestReplayMarshalUnsortedTaps(t *testing.T) {
+	r := &Replay{
+		Taps: []Tap{
+			{Tick: 10},
+			{Tick: 9},
+		},
+	}
+	if _, err := r.MarshalBinary(); err == nil {
+		t.Error("MarshalBinary: got nil error, want an error for unsorted taps")
+	}
+}
+
+func TestReplayUnmarshalBroken(t *testing.T) {
+	valid, err := (&Replay{FileName: "01.field", Taps: []Tap{{Tick: 1, X: 2, Y: 3}}}).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"wrong magic", []byte("XXXX\x01\x02\x00")},
+		{"unsupported version", []byte("GWRP\x01\x02\x00")},
+		{"too long file name", []byte("GWRP\x02\x10ab\x00")},
+		{"truncated", valid[:len(valid)-1]},
+		{"trailing bytes", append(append([]byte{}, valid...), 0)},
+	}
+	for _, c := range cases {
+		var r Replay
+		if err := r.UnmarshalBinary(c.data); err == nil {
+			t.Errorf("%s: got nil error, want an error", c.name)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright 2019 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sim implements the game logic of fields. The package doesn't depend on Ebiten so that
+// tools like the solver can run without a display.
+package sim
+
+import (
+	"image"
+	"sort"
+
+	"github.com/hajimehoshi/gopherwalk/internal/level"
+)
+
+// Tap is a tap at a position in pixels on the given tick.
+type Tap struct {
+	Tick int
+	X    int
+	Y    int
+}
+
+// Input is the input to a simulation.
+type Input interface {
+	// Taps returns the positions in pixels in the field of the taps started at the current tick.
+	Taps() []image.Point
+}
+
+// TapInput is an Input of the given positions.
+type TapInput []image.Point
+
+func (t TapInput) Taps() []image.Point {
+	return t
+}
+
+// Simulation runs the game logic of a field without rendering.
+type Simulation struct {
+	field  *Field
+	player *Player
+	tick   int
+
+	// toggles is the number of state changes of tappable objects.
+	toggles int
+
+	input *PlaybackInput
+
+	// stateful is the objects whose state is in snapshots.
+	stateful []stateful
+
+	// tappables is the objects that react to taps.
+	tappables []tappable
+}
+
+// NewPlayback creates a simulation of the field that plays back taps by Step.
+func NewPlayback(lv *level.Level, taps []Tap) *Simulation {
+	s := New(lv)
+	s.input = NewPlaybackInput(taps)
+	return s
+}
+
+// New creates a simulation of the field at the start. The simulation advances by Update.
+func New(lv *level.Level) *Simulation {
+	f := strToField(lv.Field, lv.Channels)
+
+	x, y := f.StartPosition()
+	dir := DirLeft
+	if lv.StartDir == level.DirRight {
+		dir = DirRight
+	}
+
+	s := &Simulation{
+		field:  f,
+		player: NewPlayer(x, y, dir),
+	}
+	for _, o := range f.objects {
+		if st, ok := o.(stateful); ok {
+			s.stateful = append(s.stateful, st)
+		}
+		if isTappable(o) {
+			s.tappables = append(s.tappables, o.(tappable))
+		}
+	}
+	return s
+}
+
+// Step advances the simulation by one tick.
+func (s *Simulation) Step() {
+	s.input.SetTick(s.tick)
+	s.Update(s.input)
+}
+
+// Update advances the simulation by one tick with the input.
+func (s *Simulation) Update(input Input) {
+	for _, t := range s.tappables {
+		if isTapped(input, t.tapArea()) {
+			s.toggles++
+		}
+	}
+	s.field.Update(input)
+	s.player.Update(input, s.field)
+	s.tick++
+}
+
+func (s *Simulation) Tick() int {
+	return s.tick
+}
+
+// Field returns the field. The field must not be modified.
+func (s *Simulation) Field() *Field {
+	return s.field
+}
+
+func (s *Simulation) Player() *Player {
+	return s.player
+}
+
+// Toggles returns the number of times tappable objects like force fields have been toggled.
+func (s *Simulation) Toggles() int {
+	return s.toggles
+}
+
+func (s *Simulation) AtGoal() bool {
+	return s.player.AtGoal()
+}
+
+// Targets returns the areas that react to taps.
+func (s *Simulation) Targets() []image.Rectangle {
+	var rs []image.Rectangle
+	for _, t := range s.tappables {
+		rs = append(rs, t.tapArea())
+	}
+	rs = append(rs, s.player.ClickableArea())
+	return rs
+}
+
+// PlaybackInput is an input that plays back taps.
+type PlaybackInput struct {
+	taps    []Tap
+	current TapInput
+}
+
+func NewPlaybackInput(taps []Tap) *PlaybackInput {
+	i := &PlaybackInput{
+		taps: make([]Tap, len(taps)),
+	}
+	copy(i.taps, taps)
+	sort.SliceStable(i.taps, func(a, b int) bool {
+		return i.taps[a].Tick < i.taps[b].Tick
+	})
+	return i
+}
+
+// SetTick makes the input report the taps on the given tick.
+func (i *PlaybackInput) SetTick(tick int) {
+	idx := sort.Search(len(i.taps), func(idx int) bool {
+		return i.taps[idx].Tick >= tick
+	})
+	i.current = nil
+	for ; idx < len(i.taps) && i.taps[idx].Tick == tick; idx++ {
+		i.current = append(i.current, image.Pt(i.taps[idx].X, i.taps[idx].Y))
+	}
+}
+
+func (i *PlaybackInput) Taps() []image.Point {
+	return i.current
+}
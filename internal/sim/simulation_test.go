@@ -0,0 +1,615 @@
+// Copyright 2019 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sim
+
+import (
+	"image"
+	"testing"
+
+	"github.com/hajimehoshi/gopherwalk/internal/level"
+)
+
+// newLevel parses a field of the header lines and the grid.
+func newLevel(t *testing.T, header, grid string) *level.Level {
+	t.Helper()
+	lv, err := level.Parse("test.field", []byte("name: Test\n"+header+"\n"+grid))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return lv
+}
+
+// tapAt returns a tap at the center of the tile at (x, y).
+func tapAt(tick, x, y int) Tap {
+	return Tap{
+		Tick: tick,
+		X:    x*TileWidth + TileWidth/2,
+		Y:    y*TileHeight + TileHeight/2,
+	}
+}
+
+// run plays back the taps for the given ticks.
+func run(lv *level.Level, taps []Tap, ticks int) *Simulation {
+	s := NewPlayback(lv, taps)
+	for s.Tick() < ticks {
+		s.Step()
+	}
+	return s
+}
+
+func TestSimulation(t *testing.T) {
+	cases := []struct {
+		name   string
+		header string
+		grid   string
+		taps   []Tap
+		ticks  int
+		x32    int
+		y32    int
+		dir    Dir
+		state  PlayerState
+		turns  int
+	}{
+		{
+			name: "walk left",
+			grid: "wg    w\n" +
+				"w    sw\n" +
+				"wwwwwww",
+			ticks: 32,
+			x32:   4 * PlayerUnit,
+			y32:   1 * PlayerUnit,
+			dir:   DirLeft,
+			state: PlayerStateWalking,
+		},
+		{
+			name:   "walk right",
+			header: "dir: right\n",
+			grid: "wg    w\n" +
+				"ws    w\n" +
+				"wwwwwww",
+			ticks: 32,
+			x32:   2 * PlayerUnit,
+			y32:   1 * PlayerUnit,
+			dir:   DirRight,
+			state: PlayerStateWalking,
+		},
+		{
+			name: "turn at a wall",
+			grid: "wg    w\n" +
+				"ws    w\n" +
+				"wwwwwww",
+			ticks: 10,
+			// Turning takes the first tick.
+			x32:   1*PlayerUnit + 9,
+			y32:   1 * PlayerUnit,
+			dir:   DirRight,
+			state: PlayerStateWalking,
+		},
+		{
+			name: "turn by a tap",
+			grid: "wg    w\n" +
+				"w   s w\n" +
+				"wwwwwww",
+			taps:  []Tap{tapAt(2, 4, 1)},
+			ticks: 10,
+			// The gopher walks left for 3 ticks and right for 7 ticks.
+			x32:   4*PlayerUnit - 3 + 7,
+			y32:   1 * PlayerUnit,
+			dir:   DirRight,
+			state: PlayerStateWalking,
+			turns: 1,
+		},
+		{
+			name: "tap away from the gopher",
+			grid: "wg    w\n" +
+				"w   s w\n" +
+				"wwwwwww",
+			taps:  []Tap{tapAt(2, 1, 1)},
+			ticks: 10,
+			x32:   4*PlayerUnit - 10,
+			y32:   1 * PlayerUnit,
+			dir:   DirLeft,
+			state: PlayerStateWalking,
+		},
+		{
+			name: "reach the goal",
+			grid: "w     w\n" +
+				"w g s w\n" +
+				"wwwwwww",
+			ticks: 100,
+			x32:   3*PlayerUnit + 1,
+			y32:   1 * PlayerUnit,
+			dir:   DirLeft,
+			state: PlayerStateAtGoal,
+		},
+	}
+	for _, c := range cases {
+		s := run(newLevel(t, c.header, c.grid), c.taps, c.ticks)
+		p := s.Player()
+		if x, y := p.Position(); x != c.x32 || y != c.y32 {
+			t.Errorf("%s: Position(): got (%d, %d), want (%d, %d)", c.name, x, y, c.x32, c.y32)
+		}
+		if got := p.Dir(); got != c.dir {
+			t.Errorf("%s: Dir(): got %d, want %d", c.name, got, c.dir)
+		}
+		if got := p.State(); got != c.state {
+			t.Errorf("%s: State(): got %s, want %s", c.name, got, c.state)
+		}
+		if got := p.Turns(); got != c.turns {
+			t.Errorf("%s: Turns(): got %d, want %d", c.name, got, c.turns)
+		}
+		if got, want := s.AtGoal(), c.state == PlayerStateAtGoal; got != want {
+			t.Errorf("%s: AtGoal(): got %t, want %t", c.name, got, want)
+		}
+	}
+}
+
+func TestStepAndUpdate(t *testing.T) {
+	lv := newLevel(t, "", "w f   w\n"+
+		"w g s w\n"+
+		"wwwwwww")
+	taps := []Tap{tapAt(3, 4, 1), tapAt(3, 2, 0), tapAt(20, 2, 0)}
+
+	played := run(lv, taps, 40)
+
+	updated := New(lv)
+	for updated.Tick() < 40 {
+		var in TapInput
+		for _, tap := range taps {
+			if tap.Tick == updated.Tick() {
+				in = append(in, image.Pt(tap.X, tap.Y))
+			}
+		}
+		updated.Update(in)
+	}
+
+	if got, want := updated.Snapshot().key(), played.Snapshot().key(); got != want {
+		t.Errorf("Update: got state %q, want %q", got, want)
+	}
+	if got, want := updated.Toggles(), played.Toggles(); got != want {
+		t.Errorf("Toggles(): got %d, want %d", got, want)
+	}
+}
+
+func TestSnapshotRestore(t *testing.T) {
+	lv := newLevel(t, "", "w f   w\n"+
+		"w g s w\n"+
+		"wwwwwww")
+	taps := []Tap{tapAt(3, 4, 1), tapAt(10, 2, 0), tapAt(30, 2, 0)}
+
+	s := NewPlayback(lv, taps)
+	for s.Tick() < 20 {
+		s.Step()
+	}
+	ss := s.Snapshot()
+	for s.Tick() < 40 {
+		s.Step()
+	}
+	end := s.Snapshot()
+
+	s.Restore(ss)
+	if got, want := s.Tick(), 20; got != want {
+		t.Errorf("Tick() after Restore: got %d, want %d", got, want)
+	}
+	if got, want := ss.Tick(), 20; got != want {
+		t.Errorf("Snapshot.Tick(): got %d, want %d", got, want)
+	}
+	if got, want := s.Snapshot().key(), ss.key(); got != want {
+		t.Errorf("state after Restore: got %q, want %q", got, want)
+	}
+
+	// Running again from the snapshot reaches the same state.
+	for s.Tick() < 40 {
+		s.Step()
+	}
+	if got, want := s.Snapshot().key(), end.key(); got != want {
+		t.Errorf("state after running again: got %q, want %q", got, want)
+	}
+	if got, want := s.Toggles(), end.toggles; got != want {
+		t.Errorf("Toggles() after running again: got %d, want %d", got, want)
+	}
+}
+
+// endCase is a field that ends with the gopher reaching the goal or dying.
+// A case with the walking state is a field where the gopher keeps walking.
+type endCase struct {
+	name   string
+	header string
+	grid   string
+	taps   []Tap
+
+	// tick is the tick when the gopher reaches the goal or dies.
+	tick int
+
+	// y is the row of the gopher at the end.
+	y     int
+	state PlayerState
+}
+
+// maxEndTicks is the number of ticks to run a field that doesn't end.
+const maxEndTicks = 1000
+
+// testEnds plays back the cases until the gopher reaches the goal or dies.
+func testEnds(t *testing.T, cases []endCase) {
+	t.Helper()
+	for _, c := range cases {
+		s := NewPlayback(newLevel(t, c.header, c.grid), c.taps)
+		for s.Tick() < maxEndTicks && !s.AtGoal() && !s.Player().Dead() {
+			s.Step()
+		}
+		p := s.Player()
+		if got := p.State(); got != c.state {
+			t.Errorf("%s: State(): got %s, want %s", c.name, got, c.state)
+		}
+		if got := s.Tick(); got != c.tick {
+			t.Errorf("%s: Tick(): got %d, want %d", c.name, got, c.tick)
+		}
+		if _, y := p.Position(); y/PlayerUnit != c.y {
+			t.Errorf("%s: row: got %d, want %d", c.name, y/PlayerUnit, c.y)
+		}
+	}
+}
+
+func TestElevators(t *testing.T) {
+	testEnds(t, []endCase{
+		{
+			name: "up elevator",
+			grid: "w      w\n" +
+				"wg     w\n" +
+				"wwwwe  w\n" +
+				"w   e sw\n" +
+				"wwwwwwww",
+			tick:  191,
+			y:     1,
+			state: PlayerStateAtGoal,
+		},
+		{
+			name: "down elevator",
+			grid: "w      w\n" +
+				"w     sw\n" +
+				"wwwwvwww\n" +
+				"wg  v  w\n" +
+				"wwwwwwww",
+			tick:  192,
+			y:     3,
+			state: PlayerStateAtGoal,
+		},
+		{
+			name: "down elevator tapped",
+			grid: "w      w\n" +
+				"w     sw\n" +
+				"wwwwvwww\n" +
+				"wg  v  w\n" +
+				"wwwwwwww",
+			// Only a toggle elevator reacts to taps.
+			taps:  []Tap{tapAt(0, 4, 3)},
+			tick:  192,
+			y:     3,
+			state: PlayerStateAtGoal,
+		},
+		{
+			name: "toggle going up",
+			grid: "w      w\n" +
+				"w     sw\n" +
+				"wwwwtwww\n" +
+				"wg  t  w\n" +
+				"wwwwwwww",
+			// The gopher walks on the top of the elevator.
+			tick:  maxEndTicks,
+			y:     1,
+			state: PlayerStateWalking,
+		},
+		{
+			name: "toggle tapped",
+			grid: "w      w\n" +
+				"w     sw\n" +
+				"wwwwtwww\n" +
+				"wg  t  w\n" +
+				"wwwwwwww",
+			taps:  []Tap{tapAt(0, 4, 3)},
+			tick:  192,
+			y:     3,
+			state: PlayerStateAtGoal,
+		},
+		{
+			name: "toggle tapped twice",
+			grid: "w      w\n" +
+				"w     sw\n" +
+				"wwwwtwww\n" +
+				"wg  t  w\n" +
+				"wwwwwwww",
+			taps:  []Tap{tapAt(0, 4, 3), tapAt(1, 4, 3)},
+			tick:  maxEndTicks,
+			y:     1,
+			state: PlayerStateWalking,
+		},
+	})
+}
+
+func TestKeysAndDoors(t *testing.T) {
+	testEnds(t, []endCase{
+		{
+			name: "door without the key",
+			grid: "w r     w\n" +
+				"wg R   sw\n" +
+				"wwwwwwwww",
+			tick:  maxEndTicks,
+			y:     1,
+			state: PlayerStateWalking,
+		},
+		{
+			name: "key in front of the door",
+			grid: "w       w\n" +
+				"wg R r sw\n" +
+				"wwwwwwwww",
+			tick:  160,
+			y:     1,
+			state: PlayerStateAtGoal,
+		},
+		{
+			name: "key behind the gopher",
+			grid: "w       w\n" +
+				"wg R s rw\n" +
+				"wwwwwwwww",
+			tick:  288,
+			y:     1,
+			state: PlayerStateAtGoal,
+		},
+		{
+			name: "key of another colour",
+			grid: "w b      w\n" +
+				"wg BR r sw\n" +
+				"wwwwwwwwww",
+			tick:  maxEndTicks,
+			y:     1,
+			state: PlayerStateWalking,
+		},
+		{
+			name: "keys of both colours",
+			grid: "w        w\n" +
+				"wg BRrb sw\n" +
+				"wwwwwwwwww",
+			tick:  192,
+			y:     1,
+			state: PlayerStateAtGoal,
+		},
+	})
+}
+
+func TestSwitches(t *testing.T) {
+	testEnds(t, []endCase{
+		{
+			name: "default channel",
+			grid: "w        w\n" +
+				"wg H h  sw\n" +
+				"wwwwwwwwww",
+			tick:  192,
+			y:     1,
+			state: PlayerStateAtGoal,
+		},
+		{
+			name: "default channel starting closed",
+			grid: "w        w\n" +
+				"wg h H  sw\n" +
+				"wwwwwwwwww",
+			tick:  maxEndTicks,
+			y:     1,
+			state: PlayerStateWalking,
+		},
+		{
+			name:   "open latching",
+			header: "channel: h open latching\n",
+			grid: "w        w\n" +
+				"wg H  h sw\n" +
+				"wwwwwwwwww",
+			tick:  192,
+			y:     1,
+			state: PlayerStateAtGoal,
+		},
+		{
+			name:   "open momentary",
+			header: "channel: h open momentary\n",
+			grid: "w        w\n" +
+				"wg H  h sw\n" +
+				"wwwwwwwwww",
+			// The force field is closed again when the gopher leaves the switch.
+			tick:  maxEndTicks,
+			y:     1,
+			state: PlayerStateWalking,
+		},
+		{
+			name:   "close latching",
+			header: "channel: h close latching\n",
+			grid: "w        w\n" +
+				"wg H  h sw\n" +
+				"wwwwwwwwww",
+			tick:  maxEndTicks,
+			y:     1,
+			state: PlayerStateWalking,
+		},
+		{
+			name:   "close latching starting open",
+			header: "channel: h close latching\n",
+			grid: "w        w\n" +
+				"wg h H  sw\n" +
+				"wwwwwwwwww",
+			tick:  192,
+			y:     1,
+			state: PlayerStateAtGoal,
+		},
+		{
+			name:   "close momentary",
+			header: "channel: h close momentary\n",
+			grid: "w        w\n" +
+				"wg H  h sw\n" +
+				"wwwwwwwwww",
+			// The force field is open again when the gopher leaves the switch.
+			tick:  192,
+			y:     1,
+			state: PlayerStateAtGoal,
+		},
+		{
+			name:   "toggle latching starting open",
+			header: "channel: h toggle latching open\n",
+			grid: "w        w\n" +
+				"wg H  h sw\n" +
+				"wwwwwwwwww",
+			tick:  maxEndTicks,
+			y:     1,
+			state: PlayerStateWalking,
+		},
+		{
+			name:   "toggle momentary",
+			header: "channel: h toggle momentary\n",
+			grid: "w        w\n" +
+				"wg H  h sw\n" +
+				"wwwwwwwwww",
+			tick:  maxEndTicks,
+			y:     1,
+			state: PlayerStateWalking,
+		},
+		{
+			name:   "linked force field tapped",
+			header: "channel: h open momentary\n",
+			grid: "w        w\n" +
+				"wg H  h sw\n" +
+				"wwwwwwwwww",
+			// Only the switches open a linked force field.
+			taps:  []Tap{tapAt(0, 3, 1)},
+			tick:  maxEndTicks,
+			y:     1,
+			state: PlayerStateWalking,
+		},
+	})
+}
+
+func TestDeaths(t *testing.T) {
+	testEnds(t, []endCase{
+		{
+			name: "spikes",
+			grid: "w      w\n" +
+				"wg ^  sw\n" +
+				"wwwwwwww",
+			tick:  70,
+			y:     1,
+			state: PlayerStateDead,
+		},
+		{
+			name: "lava",
+			grid: "w      w\n" +
+				"wg ~  sw\n" +
+				"wwwwwwww",
+			tick:  66,
+			y:     1,
+			state: PlayerStateDead,
+		},
+		{
+			name: "fall into lava",
+			grid: "w      w\n" +
+				"wg    sw\n" +
+				"www~wwww\n" +
+				"wwwwwwww",
+			tick:  83,
+			y:     1,
+			state: PlayerStateDead,
+		},
+		{
+			name: "fall of 8 tiles",
+			grid: "w       w\n" +
+				"wg      w\n" +
+				"wwwwww sw\n" +
+				"w      ww\n" +
+				"w      ww\n" +
+				"w      ww\n" +
+				"w      ww\n" +
+				"w      ww\n" +
+				"w      ww\n" +
+				"w      ww\n" +
+				"w      ww\n" +
+				"wwwwwwwww",
+			tick:  maxEndTicks,
+			y:     10,
+			state: PlayerStateWalking,
+		},
+		{
+			name: "fall of 9 tiles",
+			grid: "w       w\n" +
+				"wg      w\n" +
+				"wwwwww sw\n" +
+				"w      ww\n" +
+				"w      ww\n" +
+				"w      ww\n" +
+				"w      ww\n" +
+				"w      ww\n" +
+				"w      ww\n" +
+				"w      ww\n" +
+				"w      ww\n" +
+				"w      ww\n" +
+				"wwwwwwwww",
+			tick:  112,
+			y:     11,
+			state: PlayerStateDead,
+		},
+		{
+			name: "fall off the field",
+			grid: "w      w\n" +
+				"wg    sw\n" +
+				"www  www",
+			tick:  69,
+			y:     3,
+			state: PlayerStateDead,
+		},
+	})
+}
+
+func TestTeleporters(t *testing.T) {
+	testEnds(t, []endCase{
+		{
+			name: "teleport",
+			grid: "w      w\n" +
+				"wg  1  w\n" +
+				"wwwwwwww\n" +
+				"w  1  sw\n" +
+				"wwwwwwww",
+			tick:  159,
+			y:     1,
+			state: PlayerStateAtGoal,
+		},
+		{
+			name: "back on the pad in the cooldown",
+			grid: "w       w\n" +
+				"w 1   g w\n" +
+				"wwwwwwwww\n" +
+				"w    1 sw\n" +
+				"wwwwwwwww",
+			// The gopher turns at the wall and walks over the pad again without teleporting.
+			tick:  223,
+			y:     1,
+			state: PlayerStateAtGoal,
+		},
+		{
+			name: "back on the pad after the cooldown",
+			grid: "w        w\n" +
+				"w  1   g w\n" +
+				"wwwwwwwwww\n" +
+				"w     1 sw\n" +
+				"wwwwwwwwww",
+			// The gopher teleports back to the lower floor and never reaches the goal.
+			tick:  maxEndTicks,
+			y:     3,
+			state: PlayerStateWalking,
+		},
+	})
+}
@@ -0,0 +1,96 @@
+// Copyright 2019 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sim
+
+import (
+	"encoding/binary"
+)
+
+// Snapshot is a copy of the mutable state of a simulation.
+type Snapshot struct {
+	tick    int
+	toggles int
+	player  Player
+	objects []int
+}
+
+// Snapshot returns a copy of the current state.
+func (s *Simulation) Snapshot() *Snapshot {
+	ss := &Snapshot{
+		tick:    s.tick,
+		toggles: s.toggles,
+		player:  *s.player,
+		objects: make([]int, len(s.stateful)),
+	}
+	for i, st := range s.stateful {
+		ss.objects[i] = st.state()
+	}
+	return ss
+}
+
+// Restore restores the state of the snapshot.
+func (s *Simulation) Restore(ss *Snapshot) {
+	s.tick = ss.tick
+	s.toggles = ss.toggles
+	*s.player = ss.player
+	for i, st := range s.stateful {
+		st.setState(ss.objects[i])
+	}
+}
+
+// Tick returns the tick of the snapshot.
+func (ss *Snapshot) Tick() int {
+	return ss.tick
+}
+
+// key returns a string identifying the state regardless of the tick and the counters.
+func (ss *Snapshot) key() string {
+	b := make([]byte, 0, binary.MaxVarintLen64*(len(ss.objects)+6))
+	b = appendVarint(b, ss.player.x32)
+	b = appendVarint(b, ss.player.y32)
+	var flags int
+	flags |= int(ss.player.dir)
+	if ss.player.climbing {
+		flags |= 1 << 2
+	}
+	if ss.player.falling {
+		flags |= 1 << 3
+	}
+	if ss.player.atGoal {
+		flags |= 1 << 4
+	}
+	if ss.player.descending {
+		flags |= 1 << 5
+	}
+	if ss.player.dead {
+		flags |= 1 << 6
+	}
+	b = appendVarint(b, flags)
+	b = appendVarint(b, ss.player.keys)
+	if ss.player.falling {
+		b = appendVarint(b, ss.player.fallFrom)
+	}
+	b = appendVarint(b, ss.player.teleporting)
+	for _, o := range ss.objects {
+		b = appendVarint(b, o)
+	}
+	return string(b)
+}
+
+func appendVarint(b []byte, v int) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutVarint(buf[:], int64(v))
+	return append(b, buf[:n]...)
+}
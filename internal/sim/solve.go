@@ -0,0 +1,498 @@
+// Copyright 2019 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sim
+
+import (
+	"errors"
+	"image"
+	"sort"
+
+	"github.com/hajimehoshi/gopherwalk/internal/level"
+)
+
+// Solution is a sequence of the fewest taps that leads the gopher to the goal.
+type Solution struct {
+	Taps []Tap
+
+	// Ticks is the tick when the gopher reaches the goal.
+	Ticks int
+}
+
+var (
+	ErrNoSolution    = errors.New("sim: no solution")
+	ErrTooManyStates = errors.New("sim: too many states to search")
+)
+
+// Solve searches for a solution with the fewest taps within maxTicks ticks. Among the solutions with the fewest
+// taps, the gopher reaches the goal the earliest with the returned one.
+// Solve returns ErrNoSolution when there is no solution, or ErrTooManyStates when the search visits more
+// than maxStates states.
+//
+// The search is exhaustive. Taps are tried at every tick, at every point that hits a different set of targets.
+// Only taps that can't change the gopher's movement at a tick are postponed while the same tap is possible at the
+// next tick, which doesn't change the result.
+func Solve(lv *level.Level, maxTicks, maxStates int) (*Solution, error) {
+	// tapNode is the taps at a tick linked to the taps before.
+	type tapNode struct {
+		parent *tapNode
+		taps   []Tap
+	}
+	type node struct {
+		snapshot *Snapshot
+		taps     *tapNode
+	}
+
+	sim := New(lv)
+
+	// visited is the earliest tick when a state is searched. A state searched at an earlier tick with fewer or
+	// the same taps leads to any state earlier.
+	visited := map[string]int{}
+	isVisited := func(ss *Snapshot) bool {
+		t, ok := visited[ss.key()]
+		return ok && t <= ss.tick
+	}
+
+	// Search by the number of taps, and by the tick among the same number of taps.
+	// Steps without taps don't increase the cost.
+	costs := [][]*node{{{snapshot: sim.Snapshot()}}}
+	for cost := 0; cost < len(costs); cost++ {
+		tapped := costs[cost]
+		costs[cost] = nil
+		sort.SliceStable(tapped, func(i, j int) bool {
+			return tapped[i].snapshot.tick < tapped[j].snapshot.tick
+		})
+
+		// stepped is the nodes after steps without taps. This is sorted by the tick as the nodes are searched
+		// in the order of the tick.
+		var stepped []*node
+		for len(tapped) > 0 || len(stepped) > 0 {
+			var n *node
+			if len(stepped) == 0 || len(tapped) > 0 && tapped[0].snapshot.tick <= stepped[0].snapshot.tick {
+				n, tapped = tapped[0], tapped[1:]
+			} else {
+				n, stepped = stepped[0], stepped[1:]
+			}
+
+			ss := n.snapshot
+			if isVisited(ss) {
+				continue
+			}
+			visited[ss.key()] = ss.tick
+			if len(visited) > maxStates {
+				return nil, ErrTooManyStates
+			}
+
+			if ss.player.atGoal {
+				var taps []Tap
+				for t := n.taps; t != nil; t = t.parent {
+					taps = append(append([]Tap{}, t.taps...), taps...)
+				}
+				return &Solution{
+					Taps:  taps,
+					Ticks: ss.tick,
+				}, nil
+			}
+			if ss.player.dead || ss.tick >= maxTicks {
+				continue
+			}
+
+			for _, b := range branches(sim, ss) {
+				if isVisited(b.snapshot) {
+					continue
+				}
+				if len(b.points) == 0 {
+					stepped = append(stepped, &node{
+						snapshot: b.snapshot,
+						taps:     n.taps,
+					})
+					continue
+				}
+				t := &tapNode{
+					parent: n.taps,
+				}
+				for _, p := range b.points {
+					t.taps = append(t.taps, Tap{
+						Tick: ss.tick,
+						X:    p.X,
+						Y:    p.Y,
+					})
+				}
+				c := cost + len(b.points)
+				for len(costs) <= c {
+					costs = append(costs, nil)
+				}
+				costs[c] = append(costs[c], &node{
+					snapshot: b.snapshot,
+					taps:     t,
+				})
+			}
+		}
+	}
+	return nil, ErrNoSolution
+}
+
+// branch is the result of a tick with taps.
+type branch struct {
+	points   []image.Point
+	snapshot *Snapshot
+}
+
+// branches returns the results of the tick from ss with the sets of taps worth trying. The first result is the one
+// without taps, and the results are different from each other.
+//
+// A tap only toggling objects that can't affect the gopher's movement at the tick is postponed while the same tap
+// is possible at the next tick, i.e. until the gopher is going to cover the objects.
+func branches(sim *Simulation, ss *Snapshot) []branch {
+	var bs []branch
+	seen := map[string]bool{}
+	try := func(points []image.Point) {
+		sim.Restore(ss)
+		sim.Update(TapInput(points))
+		r := sim.Snapshot()
+		k := r.key()
+		if seen[k] {
+			return
+		}
+		seen[k] = true
+		bs = append(bs, branch{
+			points:   points,
+			snapshot: r,
+		})
+	}
+
+	try(nil)
+	base := bs[0].snapshot
+
+	sim.Restore(ss)
+	targets := sim.Targets()
+	gopher := targets[len(targets)-1]
+	affecting := ss.player.affectingArea()
+
+	// Only the objects near the gopher at this tick and the next tick matter.
+	areas := []image.Rectangle{
+		gopher.Union(affecting).Inset(-TileWidth),
+	}
+	areas = append(areas, teleportAreas(sim.field, affecting)...)
+	var near []image.Rectangle
+	var affects []bool
+	for _, t := range targets[:len(targets)-1] {
+		for _, a := range areas {
+			if t.Overlaps(a) {
+				near = append(near, t)
+				affects = append(affects, t.Overlaps(affecting))
+				break
+			}
+		}
+	}
+	affectsAny := func(hits []int) bool {
+		for _, i := range hits {
+			if affects[i] {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Try the taps on the objects affecting the gopher. The taps preferably don't hit the gopher.
+	var area image.Rectangle
+	for i, t := range near {
+		if affects[i] {
+			area = area.Union(t)
+		}
+	}
+	var points []image.Point
+	for _, t := range tapPoints(near, area, base.player.ClickableArea()) {
+		if affectsAny(t.hits) {
+			points = append(points, t.point)
+		}
+	}
+	tapSets(near, points, func(ps []image.Point) {
+		try(ps)
+	})
+
+	// Try the taps turning the gopher.
+	for _, b := range bs[:len(bs):len(bs)] {
+		p := b.snapshot.player
+		if p.turns != ss.player.turns || p.falling || p.dead || p.atGoal {
+			continue
+		}
+		// The gopher tests taps after moving.
+		moved := p.ClickableArea()
+		for _, t := range tapPoints(near, moved, image.Rectangle{}) {
+			try(append(append([]image.Point{}, b.points...), t.point))
+		}
+	}
+
+	// Try the taps that are impossible at the next tick.
+	for _, b := range bs[:len(bs):len(bs)] {
+		moved := b.snapshot.player.ClickableArea()
+		// The gopher at the next tick moves a little or teleports.
+		blocked := append([]image.Rectangle{moved.Inset(-TileWidth/4 - 1)},
+			teleportAreas(sim.field, b.snapshot.player.affectingArea())...)
+		// Only the objects overlapping with blocked can be impossible to tap.
+		var area image.Rectangle
+		for i, t := range near {
+			if affects[i] {
+				continue
+			}
+			for _, r := range blocked {
+				if t.Overlaps(r) {
+					area = area.Union(t)
+					break
+				}
+			}
+		}
+		var points []image.Point
+		for _, t := range tapPoints(near, area, moved) {
+			if len(t.hits) == 0 || affectsAny(t.hits) || t.point.In(moved) {
+				continue
+			}
+			if canTapAgain(near, t.hits, blocked) {
+				continue
+			}
+			points = append(points, t.point)
+		}
+		tapSets(near, points, func(ps []image.Point) {
+			try(append(append([]image.Point{}, b.points...), ps...))
+		})
+	}
+	return bs
+}
+
+// teleportAreas returns the areas around the pads linked to the teleporters overlapping with area.
+func teleportAreas(f *Field, area image.Rectangle) []image.Rectangle {
+	var rs []image.Rectangle
+	for _, o := range f.objects {
+		if t, ok := o.(*ObjectTeleporter); ok && t.Area().Overlaps(area) {
+			rs = append(rs, t.link.Area().Inset(-2*TileWidth))
+		}
+	}
+	return rs
+}
+
+// tapPoint is a point to tap and the indices of the rectangles that contain it.
+type tapPoint struct {
+	point image.Point
+	hits  []int
+}
+
+// tapPoints returns a point in area for each set of rects that a tap in area can hit. The points are as far from
+// the edges of rects as possible, and preferably out of avoid.
+func tapPoints(rects []image.Rectangle, area, avoid image.Rectangle) []tapPoint {
+	// Only the rects overlapping with area matter.
+	var rs []image.Rectangle
+	var indices []int
+	for i, r := range rects {
+		if r.Overlaps(area) {
+			rs = append(rs, r)
+			indices = append(indices, i)
+		}
+	}
+
+	xs := []int{area.Min.X, area.Max.X}
+	ys := []int{area.Min.Y, area.Max.Y}
+	for _, r := range append(rs, avoid) {
+		if r.Min.X > area.Min.X && r.Min.X < area.Max.X {
+			xs = append(xs, r.Min.X)
+		}
+		if r.Max.X > area.Min.X && r.Max.X < area.Max.X {
+			xs = append(xs, r.Max.X)
+		}
+		if r.Min.Y > area.Min.Y && r.Min.Y < area.Max.Y {
+			ys = append(ys, r.Min.Y)
+		}
+		if r.Max.Y > area.Min.Y && r.Max.Y < area.Max.Y {
+			ys = append(ys, r.Max.Y)
+		}
+	}
+	xs = uniqueInts(xs)
+	ys = uniqueInts(ys)
+
+	type cell struct {
+		tapPoint
+		avoided bool
+		size    int
+	}
+	var cells []cell
+	for i := 0; i < len(xs)-1; i++ {
+		for j := 0; j < len(ys)-1; j++ {
+			w, h := xs[i+1]-xs[i], ys[j+1]-ys[j]
+			c := cell{
+				tapPoint: tapPoint{
+					point: image.Pt(xs[i]+(w-1)/2, ys[j]+(h-1)/2),
+				},
+				size: w,
+			}
+			if h < c.size {
+				c.size = h
+			}
+			c.avoided = !c.point.In(avoid)
+			for k, r := range rs {
+				if c.point.In(r) {
+					c.hits = append(c.hits, indices[k])
+				}
+			}
+			found := false
+			for k, prev := range cells {
+				if !equalInts(c.hits, prev.hits) {
+					continue
+				}
+				if c.avoided && !prev.avoided || c.avoided == prev.avoided && c.size > prev.size {
+					cells[k] = c
+				}
+				found = true
+				break
+			}
+			if !found {
+				cells = append(cells, c)
+			}
+		}
+	}
+
+	ps := make([]tapPoint, len(cells))
+	for i, c := range cells {
+		ps[i] = c.tapPoint
+	}
+	return ps
+}
+
+// canTapAgain reports whether a tap can hit the same rects as hits out of blocked.
+func canTapAgain(rects []image.Rectangle, hits []int, blocked []image.Rectangle) bool {
+	var area image.Rectangle
+	for _, i := range hits {
+		area = area.Union(rects[i])
+	}
+	overlaps := false
+	for _, b := range blocked {
+		if area.Overlaps(b) {
+			overlaps = true
+			break
+		}
+	}
+	if !overlaps {
+		return true
+	}
+
+	n := len(rects)
+	for _, t := range tapPoints(append(append([]image.Rectangle{}, rects...), blocked...), area, image.Rectangle{}) {
+		if len(t.hits) > 0 && t.hits[len(t.hits)-1] < n && equalInts(t.hits, hits) {
+			return true
+		}
+	}
+	return false
+}
+
+// tapSets calls f with the non-empty sets of points, fewer points first. The sets where a point hits only the
+// rects that the other points hit are skipped.
+func tapSets(rects []image.Rectangle, points []image.Point, f func(points []image.Point)) {
+	subsets(len(points), func(indices []int) {
+		for _, i := range indices {
+			redundant := true
+			for _, r := range rects {
+				if !points[i].In(r) {
+					continue
+				}
+				hit := false
+				for _, j := range indices {
+					if j != i && points[j].In(r) {
+						hit = true
+						break
+					}
+				}
+				if !hit {
+					redundant = false
+					break
+				}
+			}
+			if redundant {
+				return
+			}
+		}
+		var ps []image.Point
+		for _, i := range indices {
+			ps = append(ps, points[i])
+		}
+		f(ps)
+	})
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func uniqueInts(xs []int) []int {
+	sort.Ints(xs)
+	r := xs[:0]
+	for _, x := range xs {
+		if len(r) > 0 && x == r[len(r)-1] {
+			continue
+		}
+		r = append(r, x)
+	}
+	return r
+}
+
+// subsets calls f with the non-empty subsets of [0, n), smaller subsets first.
+func subsets(n int, f func(indices []int)) {
+	for k := 1; k <= n; k++ {
+		indices := make([]int, k)
+		var rec func(i, from int)
+		rec = func(i, from int) {
+			if i == k {
+				f(indices)
+				return
+			}
+			for j := from; j < n; j++ {
+				indices[i] = j
+				rec(i+1, j+1)
+			}
+		}
+		rec(0, 0)
+	}
+}
+
+// TapPoint returns a point in targets[index] that is preferably out of the other targets.
+func TapPoint(targets []image.Rectangle, index int) (x, y int) {
+	r := targets[index]
+	c := image.Pt((r.Min.X+r.Max.X)/2, (r.Min.Y+r.Max.Y)/2)
+	candidates := []image.Point{
+		c,
+		r.Min,
+		image.Pt(r.Max.X-1, r.Min.Y),
+		image.Pt(r.Min.X, r.Max.Y-1),
+		image.Pt(r.Max.X-1, r.Max.Y-1),
+	}
+	for _, p := range candidates {
+		ok := true
+		for i, t := range targets {
+			if i != index && p.In(t) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return p.X, p.Y
+		}
+	}
+	return c.X, c.Y
+}
@@ -0,0 +1,59 @@
+// Copyright 2019 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build ignore
+// +build ignore
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"path/filepath"
+)
+
+const imagesDir = "../../resources/images"
+
+func run() error {
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by gen.go. DO NOT EDIT.\n\n")
+	buf.WriteString("package sprite\n\n")
+	for _, f := range []struct {
+		name string
+		file string
+	}{
+		{"atlasPNG", "atlas.png"},
+		{"atlasJSON", "atlas.json"},
+	} {
+		data, err := ioutil.ReadFile(filepath.Join(imagesDir, f.file))
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&buf, "var %s = %q\n\n", f.name, string(data))
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile("data.go", src, 0644)
+}
+
+func main() {
+	if err := run(); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright 2019 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:generate go run gen.go
+
+// Package sprite provides the sprite atlas in the resources directory, embedded into the binary.
+package sprite
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	_ "image/png"
+
+	"github.com/hajimehoshi/ebiten"
+)
+
+// Animation is a sequence of frames.
+type Animation struct {
+	Frames []string `json:"frames"`
+
+	// Duration is the number of ticks of each frame.
+	Duration int `json:"duration"`
+}
+
+type Atlas struct {
+	src        image.Image
+	frames     map[string]image.Rectangle
+	animations map[string]*Animation
+
+	image  *ebiten.Image
+	images map[string]*ebiten.Image
+}
+
+type metadata struct {
+	Frames map[string]struct {
+		X int `json:"x"`
+		Y int `json:"y"`
+		W int `json:"w"`
+		H int `json:"h"`
+	} `json:"frames"`
+	Animations map[string]*Animation `json:"animations"`
+}
+
+// Parse parses an atlas image in PNG and its frame metadata in JSON.
+func Parse(pngData, jsonData []byte) (*Atlas, error) {
+	src, _, err := image.Decode(bytes.NewReader(pngData))
+	if err != nil {
+		return nil, fmt.Errorf("sprite: %v", err)
+	}
+
+	var m metadata
+	if err := json.Unmarshal(jsonData, &m); err != nil {
+		return nil, fmt.Errorf("sprite: %v", err)
+	}
+
+	a := &Atlas{
+		src:        src,
+		frames:     map[string]image.Rectangle{},
+		animations: m.Animations,
+		images:     map[string]*ebiten.Image{},
+	}
+	for name, f := range m.Frames {
+		r := image.Rect(f.X, f.Y, f.X+f.W, f.Y+f.H)
+		if !r.In(src.Bounds()) {
+			return nil, fmt.Errorf("sprite: frame %q is out of the image", name)
+		}
+		a.frames[name] = r
+	}
+	for name, anim := range a.animations {
+		if len(anim.Frames) == 0 {
+			return nil, fmt.Errorf("sprite: animation %q has no frames", name)
+		}
+		if anim.Duration <= 0 {
+			return nil, fmt.Errorf("sprite: animation %q has invalid duration %d", name, anim.Duration)
+		}
+		for _, f := range anim.Frames {
+			if _, ok := a.frames[f]; !ok {
+				return nil, fmt.Errorf("sprite: animation %q has unknown frame %q", name, f)
+			}
+		}
+	}
+	return a, nil
+}
+
+// Image returns the image of the frame.
+// The ebiten image is created at the first call so that parsing an atlas doesn't require the graphics driver.
+func (a *Atlas) Image(frame string) *ebiten.Image {
+	if img, ok := a.images[frame]; ok {
+		return img
+	}
+	r, ok := a.frames[frame]
+	if !ok {
+		panic(fmt.Sprintf("sprite: unknown frame %q", frame))
+	}
+	if a.image == nil {
+		img, err := ebiten.NewImageFromImage(a.src, ebiten.FilterDefault)
+		if err != nil {
+			panic(err)
+		}
+		a.image = img
+	}
+	img := a.image.SubImage(r).(*ebiten.Image)
+	a.images[frame] = img
+	return img
+}
+
+// AnimationImage returns the image of the animation at t in ticks.
+func (a *Atlas) AnimationImage(animation string, t int) *ebiten.Image {
+	anim, ok := a.animations[animation]
+	if !ok {
+		panic(fmt.Sprintf("sprite: unknown animation %q", animation))
+	}
+	n := len(anim.Frames)
+	i := (t/anim.Duration%n + n) % n
+	return a.Image(anim.Frames[i])
+}
+
+var theAtlas *Atlas
+
+func init() {
+	a, err := Parse([]byte(atlasPNG), []byte(atlasJSON))
+	if err != nil {
+		panic(err)
+	}
+	theAtlas = a
+}
+
+// Image returns the image of the frame in the embedded atlas.
+func Image(frame string) *ebiten.Image {
+	return theAtlas.Image(frame)
+}
+
+// AnimationImage returns the image of the animation in the embedded atlas.
+func AnimationImage(animation string, t int) *ebiten.Image {
+	return theAtlas.AnimationImage(animation, t)
+}
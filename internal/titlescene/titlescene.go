@@ -24,7 +24,7 @@ import (
 type TitleScene struct{}
 
 func (t *TitleScene) Update(context scene.Context) error {
-	if context.Input().IsJustTapped() {
+	if context.Input().IsJustTapped() || context.Input().IsActionJustPressed(scene.ActionConfirm) {
 		context.GoToFieldSelectorScene()
 		return nil
 	}
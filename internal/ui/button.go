@@ -0,0 +1,116 @@
+// Copyright 2019 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/bitmapfont"
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/ebitenutil"
+	"github.com/hajimehoshi/ebiten/text"
+	"golang.org/x/image/font"
+
+	"github.com/hajimehoshi/gopherwalk/internal/scene"
+)
+
+type Button struct {
+	rect  image.Rectangle
+	text  string
+	ontap func()
+	hover bool
+
+	disabled bool
+	focused  bool
+}
+
+func NewButton(rect image.Rectangle, text string) *Button {
+	return &Button{
+		rect: rect,
+		text: text,
+	}
+}
+
+func (b *Button) SetOnTap(f func()) {
+	b.ontap = f
+}
+
+func (b *Button) SetText(text string) {
+	b.text = text
+}
+
+func (b *Button) IsHovered() bool {
+	return b.hover
+}
+
+func (b *Button) IsFocused() bool {
+	return b.focused
+}
+
+func (b *Button) Rect() image.Rectangle {
+	return b.rect
+}
+
+// SetDisabled sets whether the button ignores taps.
+func (b *Button) SetDisabled(disabled bool) {
+	b.disabled = disabled
+}
+
+// Tap calls the function set by SetOnTap unless the button is disabled.
+func (b *Button) Tap() {
+	if !b.disabled && b.ontap != nil {
+		b.ontap()
+	}
+}
+
+func (b *Button) Update(input scene.Input) {
+	x, y := input.CursorPosition()
+	b.hover = image.Pt(x, y).In(b.rect)
+	if b.hover && input.IsJustTapped() {
+		b.Tap()
+	}
+}
+
+func (b *Button) Draw(screen *ebiten.Image) {
+	bound, _ := font.BoundString(bitmapfont.Gothic12r, b.text)
+	bw := (bound.Max.X - bound.Min.X).Ceil()
+	bh := (bound.Max.Y - bound.Min.Y).Ceil()
+	x := b.rect.Min.X + (b.rect.Dx()-bw)/2 + 4
+	y := b.rect.Min.Y + (b.rect.Dy()-bh)/2 + 12
+	clr := color.NRGBA{0, 0, 0, 0xff}
+	switch {
+	case b.disabled:
+		clr = color.NRGBA{0x99, 0x99, 0x99, 0xff}
+	case b.hover, b.focused:
+		clr = color.NRGBA{0xff, 0, 0, 0xff}
+	}
+	text.Draw(screen, b.text, bitmapfont.Gothic12r, x, y, clr)
+	if b.focused {
+		DrawFrame(screen, b.rect, color.NRGBA{0xff, 0, 0, 0xff})
+	}
+}
+
+// DrawFrame draws the 1px border of rect.
+func DrawFrame(screen *ebiten.Image, rect image.Rectangle, clr color.Color) {
+	x := float64(rect.Min.X)
+	y := float64(rect.Min.Y)
+	w := float64(rect.Dx())
+	h := float64(rect.Dy())
+	ebitenutil.DrawRect(screen, x, y, w, 1, clr)
+	ebitenutil.DrawRect(screen, x, y+h-1, w, 1, clr)
+	ebitenutil.DrawRect(screen, x, y, 1, h, clr)
+	ebitenutil.DrawRect(screen, x+w-1, y, 1, h, clr)
+}
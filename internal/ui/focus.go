@@ -0,0 +1,124 @@
+// Copyright 2019 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"image"
+
+	"github.com/hajimehoshi/gopherwalk/internal/scene"
+)
+
+var dirActions = []scene.Action{
+	scene.ActionUp,
+	scene.ActionDown,
+	scene.ActionLeft,
+	scene.ActionRight,
+}
+
+func center(r image.Rectangle) image.Point {
+	return image.Pt((r.Min.X+r.Max.X)/2, (r.Min.Y+r.Max.Y)/2)
+}
+
+// Nearest returns the index of the rectangle nearest to rects[from] in the direction of the action.
+// Nearest returns from if there is no rectangle in the direction.
+func Nearest(rects []image.Rectangle, from int, action scene.Action) int {
+	c := center(rects[from])
+	best := from
+	bestScore := 0
+	for i, r := range rects {
+		if i == from {
+			continue
+		}
+		d := center(r).Sub(c)
+		var along, across int
+		switch action {
+		case scene.ActionUp:
+			along, across = -d.Y, d.X
+		case scene.ActionDown:
+			along, across = d.Y, d.X
+		case scene.ActionLeft:
+			along, across = -d.X, d.Y
+		case scene.ActionRight:
+			along, across = d.X, d.Y
+		default:
+			return from
+		}
+		if along <= 0 {
+			continue
+		}
+		if across < 0 {
+			across = -across
+		}
+		// Prefer rectangles in line with the current one.
+		score := along + across*2
+		if best == from || score < bestScore {
+			best = i
+			bestScore = score
+		}
+	}
+	return best
+}
+
+// NearestAction returns the direction action pressed in input and the index of the rectangle nearest in the direction.
+// NearestAction returns false if no direction action is pressed.
+func NearestAction(input scene.Input, rects []image.Rectangle, from int) (int, bool) {
+	for _, a := range dirActions {
+		if input.IsActionJustPressed(a) {
+			return Nearest(rects, from, a), true
+		}
+	}
+	return from, false
+}
+
+// Focus is the focus on buttons for keyboards and gamepads.
+// The zero value is a hidden focus on the first button.
+type Focus struct {
+	index  int
+	active bool
+}
+
+// Update moves the focus by the direction actions and taps the focused button by the confirm action.
+// The focus appears by the first action and disappears by a tap.
+func (f *Focus) Update(input scene.Input, buttons []*Button) {
+	if len(buttons) == 0 {
+		return
+	}
+	if input.IsJustTapped() {
+		f.active = false
+	}
+	if f.index >= len(buttons) {
+		f.index = 0
+	}
+
+	var rects []image.Rectangle
+	for _, b := range buttons {
+		rects = append(rects, b.Rect())
+	}
+	if i, ok := NearestAction(input, rects, f.index); ok {
+		if f.active {
+			f.index = i
+		}
+		f.active = true
+	} else if input.IsActionJustPressed(scene.ActionConfirm) {
+		if f.active {
+			buttons[f.index].Tap()
+		}
+		f.active = true
+	}
+
+	for i, b := range buttons {
+		b.focused = f.active && i == f.index
+	}
+}
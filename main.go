@@ -15,12 +15,121 @@
 package main
 
 import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
 	"github.com/hajimehoshi/ebiten"
+
+	"github.com/hajimehoshi/gopherwalk/internal/binding"
+	"github.com/hajimehoshi/gopherwalk/internal/gamescene"
+	"github.com/hajimehoshi/gopherwalk/internal/level"
+	"github.com/hajimehoshi/gopherwalk/internal/levelpack"
+	"github.com/hajimehoshi/gopherwalk/internal/save"
+	"github.com/hajimehoshi/gopherwalk/internal/scene"
+	"github.com/hajimehoshi/gopherwalk/internal/sim"
+)
+
+var (
+	fieldsDir  = flag.String("fields", "", "directory of field files to play instead of the embedded level pack")
+	recordDir  = flag.String("record", "", "directory to save replays of played fields")
+	replayPath = flag.String("replay", "", "replay file to play back")
+	savePath   = flag.String("save", "", "save file path (default: gopherwalk/save.json in the user config directory)")
 )
 
+func loadPack() (*level.Pack, error) {
+	if *fieldsDir != "" {
+		return level.LoadPack(*fieldsDir)
+	}
+	return levelpack.Load()
+}
+
+func loadReplay(pack *level.Pack) (*gamescene.GameScene, error) {
+	data, err := ioutil.ReadFile(*replayPath)
+	if err != nil {
+		return nil, err
+	}
+	r := &sim.Replay{}
+	if err := r.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	id, ok := pack.ID(r.FileName)
+	if !ok {
+		return nil, fmt.Errorf("field %s in the replay not found", r.FileName)
+	}
+	lv, _ := pack.Level(id)
+	return gamescene.NewPlayback(id, lv, r), nil
+}
+
+// loadSave loads the save data from path.
+// If the data is broken or of a newer version, loadSave moves the file to path + ".bak" not to overwrite it,
+// and returns new save data.
+func loadSave(path string) (*save.Data, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return save.New(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	sd, err := save.Parse(data)
+	if err == nil {
+		return sd, nil
+	}
+	bak := path + ".bak"
+	fmt.Fprintf(os.Stderr, "%v; moving the save data to %s\n", err, bak)
+	if err := os.Rename(path, bak); err != nil {
+		return nil, err
+	}
+	return save.New(), nil
+}
+
 func main() {
-	s := &SceneManager{}
+	flag.Parse()
+
+	pack, err := loadPack()
+	if err != nil {
+		panic(err)
+	}
+
+	path := *savePath
+	if path == "" {
+		// Without the user config directory, the game is playable but not saved.
+		path, _ = save.DefaultPath()
+	}
+	sd := save.New()
+	if path != "" {
+		sd, err = loadSave(path)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	editDir := *fieldsDir
+	if editDir == "" {
+		editDir = "fields"
+	}
+	s := &SceneManager{
+		pack:      pack,
+		recordDir: *recordDir,
+		editDir:   editDir,
+		save:      sd,
+		savePath:  path,
+		speed:     NewSpeedController(sd.Settings.Turbo),
+		bindings:  binding.FromSettings(&sd.Settings),
+	}
+	if *replayPath != "" {
+		g, err := loadReplay(pack)
+		if err != nil {
+			panic(err)
+		}
+		s.next = []scene.Scene{g}
+	}
 	if err := ebiten.Run(s.Update, screenWidth, screenHeight, 2, "Gopher Walk"); err != nil {
 		panic(err)
 	}
+	if err := s.saveReplays(s.stack, nil); err != nil {
+		panic(err)
+	}
 }
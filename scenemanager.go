@@ -15,12 +15,27 @@
 package main
 
 import (
+	"fmt"
+	"image"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
 	"github.com/hajimehoshi/ebiten"
 	"github.com/hajimehoshi/ebiten/inpututil"
 
+	"github.com/hajimehoshi/gopherwalk/internal/binding"
+	"github.com/hajimehoshi/gopherwalk/internal/editorscene"
+	"github.com/hajimehoshi/gopherwalk/internal/endingscene"
 	"github.com/hajimehoshi/gopherwalk/internal/fieldselectorscene"
 	"github.com/hajimehoshi/gopherwalk/internal/gamescene"
+	"github.com/hajimehoshi/gopherwalk/internal/level"
+	"github.com/hajimehoshi/gopherwalk/internal/resultscene"
+	"github.com/hajimehoshi/gopherwalk/internal/save"
 	"github.com/hajimehoshi/gopherwalk/internal/scene"
+	"github.com/hajimehoshi/gopherwalk/internal/sim"
 	"github.com/hajimehoshi/gopherwalk/internal/titlescene"
 )
 
@@ -30,50 +45,308 @@ const (
 )
 
 type SceneManager struct {
-	current scene.Scene
-	next    scene.Scene
-	turbo   bool
+	// stack is the scenes from the bottom. Only the top scene is updated.
+	stack []scene.Scene
+
+	// next is the stack at the next frame. nil means no change.
+	next           []scene.Scene
+	nextTransition transitionKind
+	transition     *transition
+
+	speed *SpeedController
+	pack  *level.Pack
+	err   error
+
+	// bindings is the keys and gamepad buttons of actions. bindings is updated when the settings are saved.
+	bindings binding.Table
+
+	// touches, keys, buttons and actions are the just-pressed inputs not delivered to the current scene yet.
+	// They are delivered only to the first update of a frame so that multiple updates in a frame don't repeat them,
+	// and kept while the scene is not updated so that they are not dropped.
+	touches []scene.Touch
+	keys    map[ebiten.Key]bool
+	buttons map[ebiten.GamepadButton]bool
+	actions map[scene.Action]bool
+
+	save *save.Data
+
+	// savePath is the path of the save file. Empty means not saving.
+	savePath string
+
+	// recordDir is the directory to save replays of game scenes. Empty means not saving.
+	recordDir string
+
+	// editDir is the directory to save fields edited in the editor.
+	editDir string
 }
 
 func (s *SceneManager) Update(screen *ebiten.Image) error {
-	if inpututil.IsKeyJustPressed(ebiten.KeyT) {
-		s.turbo = !s.turbo
-	}
-
 	if s.next != nil {
-		s.current = s.next
+		if err := s.saveReplays(s.stack, s.next); err != nil {
+			return err
+		}
+		if s.nextTransition != transitionNone && len(s.stack) > 0 {
+			s.transition = &transition{
+				kind: s.nextTransition,
+				from: s.stack,
+			}
+		}
+		s.stack = s.next
 		s.next = nil
 	}
-	if s.current == nil {
-		s.current = &titlescene.TitleScene{}
+	if len(s.stack) == 0 {
+		s.stack = []scene.Scene{&titlescene.TitleScene{}}
+	}
+	if s.transition != nil && s.transition.update() {
+		s.transition = nil
 	}
 
-	n := 1
-	if s.turbo {
-		n = 5
+	raw := s.raw()
+	if c, ok := s.top().(scene.Capturer); !ok || !c.IsCapturing() {
+		s.speed.Update(raw, s.showsSpeedBar())
+	}
+	for _, t := range raw.JustStartedTouches() {
+		if s.onSpeedBar(t.X, t.Y) {
+			continue
+		}
+		s.touches = append(s.touches, t)
+	}
+	if s.keys == nil {
+		s.keys = map[ebiten.Key]bool{}
+		s.buttons = map[ebiten.GamepadButton]bool{}
+		s.actions = map[scene.Action]bool{}
+	}
+	for k := ebiten.Key(0); k <= ebiten.KeyMax; k++ {
+		if inpututil.IsKeyJustPressed(k) {
+			s.keys[k] = true
+		}
+	}
+	for b := ebiten.GamepadButton(0); b <= ebiten.GamepadButtonMax; b++ {
+		if raw.IsGamepadButtonJustPressed(b) {
+			s.buttons[b] = true
+		}
+	}
+	for _, a := range binding.Actions {
+		if raw.IsActionJustPressed(a) {
+			s.actions[a] = true
+		}
+	}
+
+	n := s.speed.Updates()
+	if s.transition != nil {
+		// The input during a transition is dropped so that it doesn't affect the incoming scene.
+		n = 0
+		s.clearInput()
 	}
 	for i := 0; i < n; i++ {
-		if err := s.current.Update(s); err != nil {
-			return nil
+		if err := s.top().Update(s); err != nil {
+			return err
+		}
+		if s.err != nil {
+			return s.err
+		}
+		s.clearInput()
+		// The rest of the updates go to the next scene at the next frame.
+		if s.next != nil {
+			break
 		}
 	}
 	if ebiten.IsDrawingSkipped() {
 		return nil
 	}
-	s.current.Draw(screen)
+	if s.transition != nil {
+		s.transition.draw(screen, s.stack)
+		return nil
+	}
+	drawStack(screen, s.stack)
+	if s.showsSpeedBar() {
+		s.speed.Draw(screen)
+	}
+	return nil
+}
+
+func (s *SceneManager) clearInput() {
+	s.touches = nil
+	s.keys = map[ebiten.Key]bool{}
+	s.buttons = map[ebiten.GamepadButton]bool{}
+	s.actions = map[scene.Action]bool{}
+}
+
+func (s *SceneManager) top() scene.Scene {
+	return s.stack[len(s.stack)-1]
+}
+
+// showsSpeedBar reports whether the on-screen speed controls are shown.
+// The actions for the speed work in any scene.
+func (s *SceneManager) showsSpeedBar() bool {
+	if s.transition != nil {
+		return false
+	}
+	_, ok := s.top().(*gamescene.GameScene)
+	return ok
+}
+
+func (s *SceneManager) raw() rawInput {
+	return rawInput{
+		bindings: s.bindings,
+	}
+}
+
+func (s *SceneManager) onSpeedBar(x, y int) bool {
+	return s.showsSpeedBar() && image.Pt(x, y).In(s.speed.Area())
+}
+
+// saveReplays saves the replays of the game scenes in from that are not in to.
+func (s *SceneManager) saveReplays(from, to []scene.Scene) error {
+	if s.recordDir == "" {
+		return nil
+	}
+scenes:
+	for _, sc := range from {
+		g, ok := sc.(*gamescene.GameScene)
+		if !ok {
+			continue
+		}
+		for _, sc2 := range to {
+			if sc == sc2 {
+				continue scenes
+			}
+		}
+		r := g.Replay()
+		data, err := r.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		name := fmt.Sprintf("%s-%s%s", strings.TrimSuffix(r.FileName, level.Ext), time.Now().Format("20060102-150405"), sim.ReplayExt)
+		if err := ioutil.WriteFile(filepath.Join(s.recordDir, name), data, 0644); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// nextStack returns the stack that the next frame will have.
+func (s *SceneManager) nextStack() []scene.Scene {
+	if s.next != nil {
+		return s.next
+	}
+	return s.stack
+}
+
+func (s *SceneManager) goTo(next scene.Scene, kind transitionKind) {
+	s.next = []scene.Scene{next}
+	s.nextTransition = kind
+}
+
+// inGame reports whether a game scene is in the stack.
+func (s *SceneManager) inGame() bool {
+	for _, sc := range s.nextStack() {
+		if _, ok := sc.(*gamescene.GameScene); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *SceneManager) PushScene(sc scene.Scene) {
+	st := s.nextStack()
+	s.next = append(st[:len(st):len(st)], sc)
+	s.nextTransition = transitionNone
+}
+
+func (s *SceneManager) PopScene() {
+	st := s.nextStack()
+	if len(st) <= 1 {
+		return
+	}
+	s.next = st[: len(st)-1 : len(st)-1]
+	s.nextTransition = transitionNone
+}
+
 func (s *SceneManager) GoToTitleScene() {
-	s.next = &titlescene.TitleScene{}
+	s.goTo(&titlescene.TitleScene{}, transitionFade)
 }
 
 func (s *SceneManager) GoToFieldSelectorScene() {
-	s.next = fieldselectorscene.New()
+	// Leaving a game closes the iris around the gopher.
+	kind := transitionWipe
+	if s.inGame() {
+		kind = transitionIris
+	}
+	s.goTo(fieldselectorscene.New(s.pack, s.isCleared), kind)
+}
+
+// isCleared reports whether the field of the given ID in the pack is cleared.
+func (s *SceneManager) isCleared(id int) bool {
+	lv, ok := s.pack.Level(id)
+	return ok && s.save.IsCleared(lv.FileName)
 }
 
+// GoToGameScene goes to the game scene of the field of the given ID.
+// If the field cannot be played, GoToGameScene goes to the field selector instead, or to the ending if id is
+// after the last field.
 func (s *SceneManager) GoToGameScene(id int) {
-	s.next = gamescene.New(id)
+	if id > s.pack.Len() {
+		fmt.Fprintf(os.Stderr, "field %d not found\n", id)
+		s.GoToEndingScene()
+		return
+	}
+	lv, ok := s.pack.Level(id)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "field %d not found\n", id)
+		s.GoToFieldSelectorScene()
+		return
+	}
+	g, err := gamescene.New(id, lv)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		s.GoToFieldSelectorScene()
+		return
+	}
+	s.goTo(g, transitionIris)
+}
+
+func (s *SceneManager) GoToResultScene(result *scene.Result) {
+	lv, ok := s.pack.Level(result.FieldID)
+	if !ok {
+		lv = &level.Level{}
+	}
+	// A replay is not the user's play and doesn't update the records.
+	if ok && !result.Replayed && s.save.Clear(lv.FileName, result.Ticks, result.Taps) && s.savePath != "" {
+		if err := s.save.Save(s.savePath); err != nil {
+			s.err = err
+			return
+		}
+	}
+	last := result.FieldID == s.pack.Len()
+	s.PushScene(resultscene.New(result, lv, last, s.pack.IsUnlocked(result.FieldID+1, s.isCleared)))
+	s.nextTransition = transitionFade
+}
+
+func (s *SceneManager) GoToEndingScene() {
+	s.goTo(endingscene.New(s.pack), transitionFade)
+}
+
+func (s *SceneManager) GoToEditorScene(id int) {
+	lv, ok := s.pack.Level(id)
+	if !ok {
+		lv = &level.Level{
+			FileName: fmt.Sprintf("%02d%s", s.pack.Len()+1, level.Ext),
+		}
+	}
+	s.goTo(editorscene.New(lv, filepath.Join(s.editDir, lv.FileName)), transitionWipe)
+}
+
+func (s *SceneManager) Settings() *save.Settings {
+	return &s.save.Settings
+}
+
+func (s *SceneManager) SaveSettings() error {
+	s.bindings = binding.FromSettings(&s.save.Settings)
+	if s.savePath == "" {
+		return nil
+	}
+	return s.save.Save(s.savePath)
 }
 
 func (s *SceneManager) Input() scene.Input {
@@ -81,9 +354,41 @@ func (s *SceneManager) Input() scene.Input {
 }
 
 func (s *SceneManager) CursorPosition() (x, y int) {
-	return ebiten.CursorPosition()
+	if len(s.touches) > 0 {
+		return s.touches[0].X, s.touches[0].Y
+	}
+	return s.raw().CursorPosition()
 }
 
 func (s *SceneManager) IsJustTapped() bool {
-	return inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft)
+	return len(s.touches) > 0
+}
+
+func (s *SceneManager) JustStartedTouches() []scene.Touch {
+	return s.touches
+}
+
+func (s *SceneManager) IsPressed() bool {
+	raw := s.raw()
+	return raw.IsPressed() && !s.onSpeedBar(raw.CursorPosition())
+}
+
+func (s *SceneManager) IsKeyJustPressed(key ebiten.Key) bool {
+	return s.keys[key]
+}
+
+func (s *SceneManager) IsKeyPressed(key ebiten.Key) bool {
+	return ebiten.IsKeyPressed(key)
+}
+
+func (s *SceneManager) IsGamepadButtonJustPressed(button ebiten.GamepadButton) bool {
+	return s.buttons[button]
+}
+
+func (s *SceneManager) IsActionJustPressed(action scene.Action) bool {
+	return s.actions[action]
+}
+
+func (s *SceneManager) IsActionPressed(action scene.Action) bool {
+	return s.raw().IsActionPressed(action)
 }
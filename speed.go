@@ -0,0 +1,195 @@
+// Copyright 2019 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/bitmapfont"
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/ebitenutil"
+	"github.com/hajimehoshi/ebiten/text"
+
+	"github.com/hajimehoshi/gopherwalk/internal/scene"
+	"github.com/hajimehoshi/gopherwalk/internal/ui"
+)
+
+// speed is the number of scene updates per frame as a fraction.
+type speed struct {
+	num   int
+	den   int
+	label string
+}
+
+var speeds = []speed{
+	{1, 2, "0.5x"},
+	{1, 1, "1x"},
+	{2, 1, "2x"},
+	{5, 1, "5x"},
+	{10, 1, "10x"},
+}
+
+const (
+	speedNormal = 1
+	speedTurbo  = 3
+)
+
+// SpeedController controls how many times the current scene is updated per frame.
+//
+// Actions: ActionPause pauses and resumes, ActionStep steps a tick while paused, ActionSlower and ActionFaster change the speed,
+// and ActionTurbo toggles the turbo mode.
+type SpeedController struct {
+	index  int
+	paused bool
+	step   bool
+
+	// frac is the numerator of the fractional updates carried over to the next frame.
+	frac int
+
+	pauseButton  *ui.Button
+	stepButton   *ui.Button
+	slowerButton *ui.Button
+	fasterButton *ui.Button
+}
+
+const (
+	speedBarX      = screenWidth - speedBarWidth
+	speedBarWidth  = 96
+	speedBarHeight = 16
+)
+
+func NewSpeedController(turbo bool) *SpeedController {
+	c := &SpeedController{
+		index: speedNormal,
+	}
+	if turbo {
+		c.index = speedTurbo
+	}
+
+	const w = 16
+	x := speedBarX + 32
+	c.pauseButton = ui.NewButton(image.Rect(x, 0, x+w, speedBarHeight), "||")
+	c.pauseButton.SetOnTap(c.togglePause)
+	x += w
+	c.stepButton = ui.NewButton(image.Rect(x, 0, x+w, speedBarHeight), ">|")
+	c.stepButton.SetOnTap(c.doStep)
+	x += w
+	c.slowerButton = ui.NewButton(image.Rect(x, 0, x+w, speedBarHeight), "-")
+	c.slowerButton.SetOnTap(c.slower)
+	x += w
+	c.fasterButton = ui.NewButton(image.Rect(x, 0, x+w, speedBarHeight), "+")
+	c.fasterButton.SetOnTap(c.faster)
+	return c
+}
+
+func (c *SpeedController) togglePause() {
+	c.paused = !c.paused
+	c.frac = 0
+	if c.paused {
+		c.pauseButton.SetText(">")
+	} else {
+		c.pauseButton.SetText("||")
+	}
+}
+
+// doStep runs one update at the next frame. Stepping pauses the game.
+func (c *SpeedController) doStep() {
+	if !c.paused {
+		c.togglePause()
+	}
+	c.step = true
+}
+
+func (c *SpeedController) slower() {
+	if c.index > 0 {
+		c.index--
+		c.frac = 0
+	}
+}
+
+func (c *SpeedController) faster() {
+	if c.index < len(speeds)-1 {
+		c.index++
+		c.frac = 0
+	}
+}
+
+func (c *SpeedController) buttons() []*ui.Button {
+	return []*ui.Button{c.pauseButton, c.stepButton, c.slowerButton, c.fasterButton}
+}
+
+// Area returns the area of the on-screen buttons.
+func (c *SpeedController) Area() image.Rectangle {
+	return image.Rect(speedBarX, 0, screenWidth, speedBarHeight)
+}
+
+// Update handles the input. withButtons indicates whether the on-screen buttons are shown.
+func (c *SpeedController) Update(input scene.Input, withButtons bool) {
+	if withButtons {
+		for _, b := range c.buttons() {
+			b.Update(input)
+		}
+	}
+	if input.IsActionJustPressed(scene.ActionPause) {
+		c.togglePause()
+	}
+	if input.IsActionJustPressed(scene.ActionStep) {
+		c.doStep()
+	}
+	if input.IsActionJustPressed(scene.ActionSlower) {
+		c.slower()
+	}
+	if input.IsActionJustPressed(scene.ActionFaster) {
+		c.faster()
+	}
+	if input.IsActionJustPressed(scene.ActionTurbo) {
+		if c.index == speedTurbo {
+			c.index = speedNormal
+		} else {
+			c.index = speedTurbo
+		}
+		c.frac = 0
+	}
+}
+
+// Updates returns the number of scene updates in the current frame.
+func (c *SpeedController) Updates() int {
+	if c.paused {
+		if c.step {
+			c.step = false
+			return 1
+		}
+		return 0
+	}
+	s := speeds[c.index]
+	c.frac += s.num
+	n := c.frac / s.den
+	c.frac %= s.den
+	return n
+}
+
+func (c *SpeedController) Draw(screen *ebiten.Image) {
+	a := c.Area()
+	ebitenutil.DrawRect(screen, float64(a.Min.X), float64(a.Min.Y), float64(a.Dx()), float64(a.Dy()), color.NRGBA{0xff, 0xff, 0xff, 0xc0})
+	label := speeds[c.index].label
+	if c.paused {
+		label = "Pause"
+	}
+	text.Draw(screen, label, bitmapfont.Gothic12r, a.Min.X+2, a.Min.Y+12, color.Black)
+	for _, b := range c.buttons() {
+		b.Draw(screen)
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright 2019 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten"
+
+	"github.com/hajimehoshi/gopherwalk/internal/scene"
+)
+
+type transitionKind int
+
+const (
+	transitionNone transitionKind = iota
+	transitionFade
+	transitionWipe
+
+	// transitionIris opens a circle around the gopher in the incoming scenes,
+	// or closes it around the gopher in the outgoing scenes.
+	// Without the gopher, the iris falls back to the fade.
+	transitionIris
+)
+
+// transitionDuration is the number of frames of a transition.
+const transitionDuration = 30
+
+// transition is an effect between outgoing and incoming scene stacks.
+// The scenes are not updated during a transition.
+type transition struct {
+	kind transitionKind
+	from []scene.Scene
+	tick int
+}
+
+var (
+	fromImage   *ebiten.Image
+	toImage     *ebiten.Image
+	maskImage   *ebiten.Image
+	circleImage *ebiten.Image
+)
+
+// circleSize is the size of circleImage. circleImage is scaled to the radius of the iris.
+const circleSize = 256
+
+func ensureTransitionImages() {
+	if fromImage != nil {
+		return
+	}
+	fromImage, _ = ebiten.NewImage(screenWidth, screenHeight, ebiten.FilterDefault)
+	toImage, _ = ebiten.NewImage(screenWidth, screenHeight, ebiten.FilterDefault)
+	maskImage, _ = ebiten.NewImage(screenWidth, screenHeight, ebiten.FilterDefault)
+
+	a := image.NewAlpha(image.Rect(0, 0, circleSize, circleSize))
+	const r = circleSize / 2
+	for j := 0; j < circleSize; j++ {
+		for i := 0; i < circleSize; i++ {
+			dx := float64(i) + 0.5 - r
+			dy := float64(j) + 0.5 - r
+			if dx*dx+dy*dy <= r*r {
+				a.SetAlpha(i, j, color.Alpha{0xff})
+			}
+		}
+	}
+	circleImage, _ = ebiten.NewImageFromImage(a, ebiten.FilterLinear)
+}
+
+// drawStack draws the scenes from the bottom.
+func drawStack(screen *ebiten.Image, stack []scene.Scene) {
+	for _, s := range stack {
+		s.Draw(screen)
+	}
+}
+
+// focusPoint returns the point of interest of the topmost scene that has it.
+func focusPoint(stack []scene.Scene) (image.Point, bool) {
+	for i := len(stack) - 1; i >= 0; i-- {
+		if f, ok := stack[i].(scene.Focused); ok {
+			x, y := f.FocusPoint()
+			return image.Pt(x, y), true
+		}
+	}
+	return image.Point{}, false
+}
+
+// update advances the transition and reports whether the transition is finished.
+func (t *transition) update() bool {
+	t.tick++
+	return t.tick >= transitionDuration
+}
+
+// drawScenes draws the stack on an offscreen image. The image is filled with black like the screen.
+func drawScenes(img *ebiten.Image, stack []scene.Scene) {
+	img.Fill(color.Black)
+	drawStack(img, stack)
+}
+
+// drawIris draws img masked by a circle at center with radius r.
+func drawIris(screen, img *ebiten.Image, center image.Point, r float64) {
+	maskImage.Clear()
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(2*r/circleSize, 2*r/circleSize)
+	op.GeoM.Translate(float64(center.X)-r, float64(center.Y)-r)
+	maskImage.DrawImage(circleImage, op)
+
+	op = &ebiten.DrawImageOptions{}
+	op.CompositeMode = ebiten.CompositeModeDestinationIn
+	img.DrawImage(maskImage, op)
+	screen.DrawImage(img, nil)
+}
+
+// maxRadius returns the radius of the circle at center that covers the screen.
+func maxRadius(center image.Point) float64 {
+	w := math.Max(float64(center.X), float64(screenWidth-center.X))
+	h := math.Max(float64(center.Y), float64(screenHeight-center.Y))
+	return math.Hypot(w, h)
+}
+
+func (t *transition) draw(screen *ebiten.Image, to []scene.Scene) {
+	ensureTransitionImages()
+	rate := float64(t.tick) / transitionDuration
+
+	kind := t.kind
+	if kind == transitionIris {
+		if c, ok := focusPoint(to); ok {
+			drawStack(screen, t.from)
+			drawScenes(toImage, to)
+			drawIris(screen, toImage, c, rate*maxRadius(c))
+			return
+		}
+		if c, ok := focusPoint(t.from); ok {
+			drawStack(screen, to)
+			drawScenes(fromImage, t.from)
+			drawIris(screen, fromImage, c, (1-rate)*maxRadius(c))
+			return
+		}
+		kind = transitionFade
+	}
+
+	drawStack(screen, t.from)
+	drawScenes(toImage, to)
+	switch kind {
+	case transitionFade:
+		op := &ebiten.DrawImageOptions{}
+		op.ColorM.Scale(1, 1, 1, rate)
+		screen.DrawImage(toImage, op)
+	case transitionWipe:
+		w := int(rate * screenWidth)
+		screen.DrawImage(toImage.SubImage(image.Rect(0, 0, w, screenHeight)).(*ebiten.Image), nil)
+	default:
+		panic("not reached")
+	}
+}